@@ -0,0 +1,446 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	decimal "github.com/alpacahq/alpacadecimal"
+)
+
+// adaptiveConfig holds the parameters shared by the adaptive bar processors. Rather than using a fixed threshold,
+// these processors estimate the expected threshold from exponentially weighted moving averages of previous bars, as
+// described in "Advances in Financial Machine Learning" by Marcos López de Prado.
+type adaptiveConfig struct {
+	processorConfig
+
+	// warmup is the expected number of ticks per bar before any bar has closed
+	warmup int64 `validate:"required"`
+	// span of the exponentially weighted moving averages, defaults to warmup
+	span int64
+	// minTicks and maxTicks clamp the expected number of ticks per bar, zero disables the clamp
+	minTicks int64
+	maxTicks int64
+}
+
+// check rejects parameters that make the moving averages diverge or the clamp empty.
+func (c *adaptiveConfig) check() error {
+	switch {
+	case c.warmup <= 0:
+		return fmt.Errorf("warmup must be positive, got %d", c.warmup)
+	case c.span < 0:
+		return fmt.Errorf("span must not be negative, got %d", c.span)
+	case c.minTicks < 0 || c.maxTicks < 0:
+		return fmt.Errorf("tick limits must not be negative, got %d and %d", c.minTicks, c.maxTicks)
+	case c.maxTicks > 0 && c.minTicks > c.maxTicks:
+		return fmt.Errorf("minimum ticks %d exceed maximum ticks %d", c.minTicks, c.maxTicks)
+	}
+
+	return nil
+}
+
+// alpha returns the smoothing factor for the configured span.
+func (c adaptiveConfig) alpha() float64 {
+	span := c.span
+	if span <= 0 {
+		span = c.warmup
+	}
+
+	return 2 / (float64(span) + 1)
+}
+
+// clamp limits the expected number of ticks per bar to the configured range.
+func (c adaptiveConfig) clamp(ticks float64) float64 {
+	if c.minTicks > 0 {
+		ticks = math.Max(ticks, float64(c.minTicks))
+	}
+
+	if c.maxTicks > 0 {
+		ticks = math.Min(ticks, float64(c.maxTicks))
+	}
+
+	return ticks
+}
+
+// processImbalance closes a bar once the absolute imbalance of signed trade values reaches E[T]·|E[b·v]|, where E[T]
+// is the expected number of ticks per bar and E[b·v] the expected signed value of a tick.
+func (c adaptiveConfig) processImbalance(ctx context.Context, trades <-chan Trade, value measure) chan *Bar {
+	output := make(chan *Bar)
+
+	go func() {
+		defer close(output)
+
+		var current *Bar
+		var rule tickRule
+		var imbalance float64
+		var ticks, observed int64
+
+		expectedTicks := newEWMA(c.alpha(), c.clamp(float64(c.warmup)))
+		expectedImbalance := ewma{alpha: c.alpha()}
+
+		snapshot := func() adaptiveImbalanceState {
+			return adaptiveImbalanceState{
+				Current:           snapshotBar(current),
+				Rule:              rule,
+				Imbalance:         imbalance,
+				Ticks:             ticks,
+				Observed:          observed,
+				ExpectedTicks:     expectedTicks.value,
+				ExpectedImbalance: expectedImbalance.state(),
+			}
+		}
+
+		// resume from the checkpoint, if any
+		var state adaptiveImbalanceState
+		if c.restore(&state) {
+			current, rule, imbalance = state.Current.bar(), state.Rule, state.Imbalance
+			ticks, observed = state.Ticks, state.Observed
+			expectedTicks.value = state.ExpectedTicks
+			expectedImbalance.restore(state.ExpectedImbalance)
+		}
+
+		partial := c.partials()
+
+		for trade := range c.newTrades(ctx, trades) {
+			// initialize the current bar if it doesn't exist
+			if current == nil {
+				current = &Bar{}
+			}
+
+			// check if the trade is in a new session
+			if c.session.crossed(current.Start, trade.Time) {
+				finalizedBar := current
+
+				if !c.closeBar(ctx, output, finalizedBar, CloseReasonSession) {
+					return
+				}
+
+				// reset the current bar
+				current = &Bar{}
+				imbalance = 0
+				ticks = 0
+			}
+
+			c.apply(current, trade)
+
+			signed := rule.next(trade) * value(trade).InexactFloat64()
+			imbalance += signed
+			expectedImbalance.update(signed)
+
+			ticks++
+			observed++
+
+			// wait for enough ticks to estimate the expected imbalance
+			if observed < c.warmup {
+				if !partial.publish(ctx, output, trade, current) {
+					return
+				}
+
+				c.save(trade, snapshot())
+				continue
+			}
+
+			threshold := expectedTicks.value * math.Abs(expectedImbalance.value)
+			current.Threshold = decimal.NewFromFloat(threshold)
+
+			if math.Abs(imbalance) >= threshold {
+				finalizedBar := current
+				if !c.closeBar(ctx, output, finalizedBar, CloseReasonThreshold) {
+					return
+				}
+
+				expectedTicks.value = c.clamp(expectedTicks.update(float64(ticks)))
+
+				// reset the current bar
+				current = nil
+				imbalance = 0
+				ticks = 0
+			}
+
+			if !partial.publish(ctx, output, trade, current) {
+				return
+			}
+
+			c.save(trade, snapshot())
+		}
+
+		if current != nil {
+			c.closeBar(ctx, output, current, CloseReasonEndOfStream)
+		}
+	}()
+
+	return output
+}
+
+// processRuns closes a bar once the larger of the cumulative buy and sell values in the bar reaches
+// E[T]·max{P[b=1]·E[v|b=1], (1-P[b=1])·E[v|b=-1]}, where E[T] is the expected number of ticks per bar, P[b=1] the
+// expected proportion of buy ticks in a bar and E[v|b] the expected value of a buy or sell tick.
+func (c adaptiveConfig) processRuns(ctx context.Context, trades <-chan Trade, value measure) chan *Bar {
+	output := make(chan *Bar)
+
+	go func() {
+		defer close(output)
+
+		var current *Bar
+		var rule tickRule
+		var buyRun, sellRun float64
+		var buyTicks, ticks, observed int64
+
+		expectedTicks := newEWMA(c.alpha(), c.clamp(float64(c.warmup)))
+		buyProportion := ewma{alpha: c.alpha()}
+		buyValue := ewma{alpha: c.alpha()}
+		sellValue := ewma{alpha: c.alpha()}
+
+		snapshot := func() adaptiveRunState {
+			return adaptiveRunState{
+				Current:       snapshotBar(current),
+				Rule:          rule,
+				BuyRun:        buyRun,
+				SellRun:       sellRun,
+				BuyTicks:      buyTicks,
+				Ticks:         ticks,
+				Observed:      observed,
+				ExpectedTicks: expectedTicks.value,
+				BuyProportion: buyProportion.state(),
+				BuyValue:      buyValue.state(),
+				SellValue:     sellValue.state(),
+			}
+		}
+
+		// resume from the checkpoint, if any
+		var state adaptiveRunState
+		if c.restore(&state) {
+			current, rule, buyRun, sellRun = state.Current.bar(), state.Rule, state.BuyRun, state.SellRun
+			buyTicks, ticks, observed = state.BuyTicks, state.Ticks, state.Observed
+			expectedTicks.value = state.ExpectedTicks
+			buyProportion.restore(state.BuyProportion)
+			buyValue.restore(state.BuyValue)
+			sellValue.restore(state.SellValue)
+		}
+
+		partial := c.partials()
+
+		for trade := range c.newTrades(ctx, trades) {
+			// initialize the current bar if it doesn't exist
+			if current == nil {
+				current = &Bar{}
+			}
+
+			// check if the trade is in a new session
+			if c.session.crossed(current.Start, trade.Time) {
+				finalizedBar := current
+
+				if !c.closeBar(ctx, output, finalizedBar, CloseReasonSession) {
+					return
+				}
+
+				// reset the current bar
+				current = &Bar{}
+				buyRun = 0
+				sellRun = 0
+				buyTicks = 0
+				ticks = 0
+			}
+
+			c.apply(current, trade)
+
+			v := value(trade).InexactFloat64()
+			if rule.next(trade) > 0 {
+				buyRun += v
+				buyTicks++
+				buyValue.update(v)
+			} else {
+				sellRun += v
+				sellValue.update(v)
+			}
+
+			ticks++
+			observed++
+
+			// wait for enough ticks to estimate the expected run
+			if observed < c.warmup {
+				if !partial.publish(ctx, output, trade, current) {
+					return
+				}
+
+				c.save(trade, snapshot())
+				continue
+			}
+
+			// until a bar has closed the buy proportion is taken from the ticks seen so far
+			p := buyProportion.value
+			if !buyProportion.seeded {
+				p = float64(buyTicks) / float64(ticks)
+			}
+
+			threshold := expectedTicks.value * math.Max(p*buyValue.value, (1-p)*sellValue.value)
+			current.Threshold = decimal.NewFromFloat(threshold)
+
+			if math.Max(buyRun, sellRun) >= threshold {
+				finalizedBar := current
+				if !c.closeBar(ctx, output, finalizedBar, CloseReasonThreshold) {
+					return
+				}
+
+				expectedTicks.value = c.clamp(expectedTicks.update(float64(ticks)))
+				buyProportion.update(float64(buyTicks) / float64(ticks))
+
+				// reset the current bar
+				current = nil
+				buyRun = 0
+				sellRun = 0
+				buyTicks = 0
+				ticks = 0
+			}
+
+			if !partial.publish(ctx, output, trade, current) {
+				return
+			}
+
+			c.save(trade, snapshot())
+		}
+
+		if current != nil {
+			c.closeBar(ctx, output, current, CloseReasonEndOfStream)
+		}
+	}()
+
+	return output
+}
+
+// adaptiveImbalanceState is the state of an adaptive imbalance processor between trades.
+type adaptiveImbalanceState struct {
+	Current           *barState `json:"current,omitempty"`
+	Rule              tickRule  `json:"rule"`
+	Imbalance         float64   `json:"imbalance"`
+	Ticks             int64     `json:"ticks"`
+	Observed          int64     `json:"observed"`
+	ExpectedTicks     float64   `json:"expected_ticks"`
+	ExpectedImbalance *float64  `json:"expected_imbalance,omitempty"`
+}
+
+// adaptiveRunState is the state of an adaptive run processor between trades.
+type adaptiveRunState struct {
+	Current       *barState `json:"current,omitempty"`
+	Rule          tickRule  `json:"rule"`
+	BuyRun        float64   `json:"buy_run"`
+	SellRun       float64   `json:"sell_run"`
+	BuyTicks      int64     `json:"buy_ticks"`
+	Ticks         int64     `json:"ticks"`
+	Observed      int64     `json:"observed"`
+	ExpectedTicks float64   `json:"expected_ticks"`
+	BuyProportion *float64  `json:"buy_proportion,omitempty"`
+	BuyValue      *float64  `json:"buy_value,omitempty"`
+	SellValue     *float64  `json:"sell_value,omitempty"`
+}
+
+// measure returns the value a trade contributes to an adaptive bar.
+type measure func(Trade) decimal.Decimal
+
+func tickMeasure(Trade) decimal.Decimal {
+	return decimal.NewFromInt(1)
+}
+
+func volumeMeasure(t Trade) decimal.Decimal {
+	return t.Size
+}
+
+func dollarMeasure(t Trade) decimal.Decimal {
+	return t.Price.Mul(t.Size)
+}
+
+// ewma is an exponentially weighted moving average, seeded by its first observation.
+type ewma struct {
+	alpha  float64
+	value  float64
+	seeded bool
+}
+
+func newEWMA(alpha, initial float64) ewma {
+	return ewma{alpha: alpha, value: initial, seeded: true}
+}
+
+func (e *ewma) update(x float64) float64 {
+	if !e.seeded {
+		e.value = x
+		e.seeded = true
+
+		return e.value
+	}
+
+	e.value = e.alpha*x + (1-e.alpha)*e.value
+
+	return e.value
+}
+
+// state returns the value of the average, or nil if it has not been seeded.
+func (e ewma) state() *float64 {
+	if !e.seeded {
+		return nil
+	}
+
+	return &e.value
+}
+
+// restore resets the average to a value returned by state.
+func (e *ewma) restore(value *float64) {
+	if value != nil {
+		e.value = *value
+		e.seeded = true
+	}
+}
+
+// tickRule signs trades by the direction of the last price change, carrying the previous sign forward when the price
+// is unchanged. The first trade is signed by its Side.
+type tickRule struct {
+	prevPrice decimal.Decimal
+	sign      float64
+}
+
+// tickRuleState is the serialized form of a tickRule.
+type tickRuleState struct {
+	PrevPrice decimal.Decimal `json:"prev_price"`
+	Sign      float64         `json:"sign"`
+}
+
+func (r tickRule) MarshalJSON() ([]byte, error) {
+	return json.Marshal(tickRuleState{PrevPrice: r.prevPrice, Sign: r.sign})
+}
+
+func (r *tickRule) UnmarshalJSON(data []byte) error {
+	var state tickRuleState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+
+	r.prevPrice, r.sign = state.PrevPrice, state.Sign
+
+	return nil
+}
+
+func (r *tickRule) next(t Trade) float64 {
+	switch {
+	case r.prevPrice.IsZero():
+		if t.Side == SideBuy {
+			r.sign = 1
+		} else {
+			r.sign = -1
+		}
+	case t.Price.GreaterThan(r.prevPrice):
+		r.sign = 1
+	case t.Price.LessThan(r.prevPrice):
+		r.sign = -1
+	}
+
+	r.prevPrice = t.Price
+
+	return r.sign
+}
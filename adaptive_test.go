@@ -0,0 +1,68 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender_test
+
+import (
+	"testing"
+
+	"github.com/csgriffis/bartender"
+)
+
+func TestAdaptiveConfig_Invalid(t *testing.T) {
+	// newAdaptive creates each adaptive imbalance and run processor with the parameters
+	newAdaptive := map[string]func(warmup, span, minTicks, maxTicks int64) error{
+		"Tick Imbalance": func(w, s, lo, hi int64) error {
+			_, err := bartender.New(bartender.WithAdaptiveTickImbalance(w, s), bartender.WithAdaptiveTickImbalanceLimits(lo, hi))
+			return err
+		},
+		"Tick Run": func(w, s, lo, hi int64) error {
+			_, err := bartender.New(bartender.WithAdaptiveTickRun(w, s), bartender.WithAdaptiveTickRunLimits(lo, hi))
+			return err
+		},
+		"Volume Imbalance": func(w, s, lo, hi int64) error {
+			_, err := bartender.New(bartender.WithAdaptiveVolumeImbalance(w, s), bartender.WithAdaptiveVolumeImbalanceLimits(lo, hi))
+			return err
+		},
+		"Volume Run": func(w, s, lo, hi int64) error {
+			_, err := bartender.New(bartender.WithAdaptiveVolumeRun(w, s), bartender.WithAdaptiveVolumeRunLimits(lo, hi))
+			return err
+		},
+		"Dollar Imbalance": func(w, s, lo, hi int64) error {
+			_, err := bartender.New(bartender.WithAdaptiveDollarImbalance(w, s), bartender.WithAdaptiveDollarImbalanceLimits(lo, hi))
+			return err
+		},
+		"Dollar Run": func(w, s, lo, hi int64) error {
+			_, err := bartender.New(bartender.WithAdaptiveDollarRun(w, s), bartender.WithAdaptiveDollarRunLimits(lo, hi))
+			return err
+		},
+	}
+
+	tt := []struct {
+		name                             string
+		warmup, span, minTicks, maxTicks int64
+		wantErr                          bool
+	}{
+		{name: "Valid", warmup: 10, span: 5, minTicks: 2, maxTicks: 20},
+		{name: "Unclamped", warmup: 10, minTicks: 2},
+		{name: "Zero Warmup", wantErr: true},
+		{name: "Negative Warmup", warmup: -1, wantErr: true},
+		{name: "Negative Span", warmup: 10, span: -1, wantErr: true},
+		{name: "Negative Limit", warmup: 10, minTicks: -1, wantErr: true},
+		{name: "Inverted Limits", warmup: 10, minTicks: 20, maxTicks: 2, wantErr: true},
+	}
+
+	for _, tc := range tt {
+		for kind, create := range newAdaptive {
+			t.Run(tc.name+"/"+kind, func(t *testing.T) {
+				if err := create(tc.warmup, tc.span, tc.minTicks, tc.maxTicks); (err != nil) != tc.wantErr {
+					t.Errorf("New() error = %v, wantErr %v", err, tc.wantErr)
+				}
+			})
+		}
+	}
+}
@@ -15,6 +15,20 @@ import (
 	decimal "github.com/alpacahq/alpacadecimal"
 )
 
+// CloseReason is the reason a processor closed a bar.
+type CloseReason string
+
+const (
+	// CloseReasonThreshold closes a bar once the processor's threshold is reached, or a brick once it is complete.
+	CloseReasonThreshold CloseReason = "threshold"
+	// CloseReasonInterval closes a time bar at the end of its interval.
+	CloseReasonInterval CloseReason = "interval"
+	// CloseReasonSession closes a bar before its threshold or interval is reached because a new session has begun.
+	CloseReasonSession CloseReason = "session"
+	// CloseReasonEndOfStream closes the incomplete bar in progress when the trade channel is closed.
+	CloseReasonEndOfStream CloseReason = "end_of_stream"
+)
+
 type Bar struct {
 	Symbol string          `json:"symbol"`
 	Open   decimal.Decimal `json:"open"`
@@ -24,13 +38,44 @@ type Bar struct {
 	Volume decimal.Decimal `json:"volume"`
 	Start  time.Time       `json:"start"`
 
+	// End is the end of the interval for time bars and the time of the last trade for other bars.
+	End time.Time `json:"end"`
+
 	// Intra-bar statistics
-	BuyVolume  decimal.Decimal `json:"buy_volume"`
-	SellVolume decimal.Decimal `json:"sell_volume"`
-	Ticks      int             `json:"ticks"`
-	Upticks    int             `json:"upticks"`
+	BuyVolume     decimal.Decimal `json:"buy_volume"`
+	SellVolume    decimal.Decimal `json:"sell_volume"`
+	Ticks         int             `json:"ticks"`
+	Upticks       int             `json:"upticks"`
+	Downticks     int             `json:"downticks"`
+	VWAP          decimal.Decimal `json:"vwap"`
+	Notional      decimal.Decimal `json:"notional"`
+	LastTradeTime time.Time       `json:"last_trade_time"`
+
+	// Threshold is the expected threshold in effect when the bar closed. It is only set by the adaptive processors.
+	Threshold decimal.Decimal `json:"threshold"`
+
+	// Provisional marks a snapshot of a bar still in progress, emitted by processors configured WithPartialBars.
+	Provisional bool `json:"provisional"`
+
+	// Revision counts the revisions of a bar that was emitted before all of its trades had arrived. A revised bar
+	// replaces the bar with the same Start and a lower Revision.
+	Revision int `json:"revision"`
+
+	// Processor is the name of the processor configuration that emitted the bar, e.g. DollarRunBarConfig.
+	Processor string `json:"processor"`
+
+	// Sequence numbers the bars a processor closes for a symbol, starting at 1. Provisional snapshots have no
+	// Sequence, and revised bars keep the Sequence of the bar they replace.
+	Sequence int64 `json:"sequence"`
+
+	// CloseReason is why the processor closed the bar. It is empty for provisional snapshots.
+	CloseReason CloseReason `json:"close_reason"`
+
+	// Features are the microstructure features of the bar, computed by processors configured WithFeatures.
+	Features *Features `json:"features,omitempty"`
 
 	prevPrice decimal.Decimal
+	features  *featureAccumulator
 }
 
 func (b *Bar) applyTrade(t Trade) {
@@ -61,21 +106,56 @@ func (b *Bar) applyTrade(t Trade) {
 		b.Upticks++
 	}
 
-	if t.Side == SideBuy {
+	// only increment Downticks if the price has decreased
+	if t.Price.LessThan(b.prevPrice) {
+		b.Downticks++
+	}
+
+	if t.Time.After(b.LastTradeTime) {
+		b.LastTradeTime = t.Time
+	}
+
+	if t.Time.After(b.End) {
+		b.End = t.Time
+	}
+
+	switch t.Side {
+	case SideBuy:
 		b.BuyVolume = b.BuyVolume.Add(t.Size)
-	} else {
+	case SideSell:
 		b.SellVolume = b.SellVolume.Add(t.Size)
+	default:
+		// split trades of unknown side by their buy ratio
+		buySize := t.Size.Mul(t.BuyRatio)
+		b.BuyVolume = b.BuyVolume.Add(buySize)
+		b.SellVolume = b.SellVolume.Add(t.Size.Sub(buySize))
 	}
 
 	b.Close = t.Price
 	b.High = decimal.Max(b.High, t.Price)
 	b.Low = decimal.Min(b.Low, t.Price)
 	b.Volume = b.Volume.Add(t.Size)
+	b.Notional = b.Notional.Add(t.Price.Mul(t.Size))
+
+	if !b.Volume.IsZero() {
+		b.VWAP = b.Notional.Div(b.Volume)
+	}
+
+	if b.features != nil {
+		b.features.add(t)
+		b.Features = b.features.features(b)
+	}
 }
 
+// UnmarshalCSV reads a record written by MarshalCSV. Records of 11 or 16 columns, written before the trade statistics
+// or the bar metadata were added, leave those fields unset. Features are not written to CSV.
 func (b *Bar) UnmarshalCSV(record []string) error {
 	var err error
 
+	if len(record) != 11 && len(record) != 16 && len(record) != len(barColumns) {
+		return fmt.Errorf("expected 11, 16 or %d columns, got %d", len(barColumns), len(record))
+	}
+
 	b.Symbol = record[0]
 
 	b.Start, err = time.Parse(time.RFC3339Nano, record[1])
@@ -128,21 +208,102 @@ func (b *Bar) UnmarshalCSV(record []string) error {
 		return fmt.Errorf("failed to parse sell volume: %w", err)
 	}
 
+	if len(record) == 11 {
+		return nil
+	}
+
+	b.VWAP, err = decimal.NewFromString(record[11])
+	if err != nil {
+		return fmt.Errorf("failed to parse VWAP: %w", err)
+	}
+
+	b.Notional, err = decimal.NewFromString(record[12])
+	if err != nil {
+		return fmt.Errorf("failed to parse notional: %w", err)
+	}
+
+	b.End, err = time.Parse(time.RFC3339Nano, record[13])
+	if err != nil {
+		return fmt.Errorf("failed to parse end time: %w", err)
+	}
+
+	b.LastTradeTime, err = time.Parse(time.RFC3339Nano, record[14])
+	if err != nil {
+		return fmt.Errorf("failed to parse last trade time: %w", err)
+	}
+
+	b.Downticks, err = strconv.Atoi(record[15])
+	if err != nil {
+		return fmt.Errorf("failed to parse Downticks: %w", err)
+	}
+
+	if len(record) == 16 {
+		return nil
+	}
+
+	b.Threshold, err = decimal.NewFromString(record[16])
+	if err != nil {
+		return fmt.Errorf("failed to parse threshold: %w", err)
+	}
+
+	b.Provisional, err = strconv.ParseBool(record[17])
+	if err != nil {
+		return fmt.Errorf("failed to parse provisional: %w", err)
+	}
+
+	b.Revision, err = strconv.Atoi(record[18])
+	if err != nil {
+		return fmt.Errorf("failed to parse revision: %w", err)
+	}
+
+	b.Processor = record[19]
+
+	b.Sequence, err = strconv.ParseInt(record[20], 10, 64)
+	if err != nil {
+		return fmt.Errorf("failed to parse sequence: %w", err)
+	}
+
+	b.CloseReason = CloseReason(record[21])
+
 	return nil
 }
 
+// MarshalCSV writes the bar's prices exactly. Use a BarWriter to round them.
 func (b *Bar) MarshalCSV() ([]string, error) {
+	return b.record(nil), nil
+}
+
+// barColumns are the names of the columns written by MarshalCSV.
+var barColumns = []string{
+	"symbol", "start", "open", "high", "low", "close", "volume", "ticks", "upticks", "buy_volume", "sell_volume",
+	"vwap", "notional", "end", "last_trade_time", "downticks",
+	"threshold", "provisional", "revision", "processor", "sequence", "close_reason",
+}
+
+// record returns the bar's CSV columns, formatting its prices with the precision of its symbol.
+func (b *Bar) record(precision Precision) []string {
 	return []string{
 		b.Symbol,
 		b.Start.Format(time.RFC3339Nano),
-		b.Open.StringFixed(2),
-		b.High.StringFixed(2),
-		b.Low.StringFixed(2),
-		b.Close.StringFixed(2),
+		precision.Format(b.Symbol, b.Open),
+		precision.Format(b.Symbol, b.High),
+		precision.Format(b.Symbol, b.Low),
+		precision.Format(b.Symbol, b.Close),
 		b.Volume.String(),
 		strconv.Itoa(b.Ticks),
 		strconv.Itoa(b.Upticks),
 		b.BuyVolume.String(),
 		b.SellVolume.String(),
-	}, nil
+		precision.Format(b.Symbol, b.VWAP),
+		b.Notional.String(),
+		b.End.Format(time.RFC3339Nano),
+		b.LastTradeTime.Format(time.RFC3339Nano),
+		strconv.Itoa(b.Downticks),
+		b.Threshold.String(),
+		strconv.FormatBool(b.Provisional),
+		strconv.Itoa(b.Revision),
+		b.Processor,
+		strconv.FormatInt(b.Sequence, 10),
+		string(b.CloseReason),
+	}
 }
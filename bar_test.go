@@ -0,0 +1,287 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender_test
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	decimal "github.com/alpacahq/alpacadecimal"
+	"github.com/csgriffis/bartender"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestBar_Statistics(t *testing.T) {
+	tick, err := bartender.New(bartender.WithTickThreshold(4))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	timed, err := bartender.New(bartender.WithInterval(time.Minute))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	trades := []bartender.Trade{
+		{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 5, 0, time.UTC)},
+		{Price: decimal.NewFromInt(102), Size: decimal.NewFromInt(2), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 10, 0, time.UTC)},
+		{Price: decimal.NewFromInt(99), Size: decimal.NewFromInt(1), Side: bartender.SideSell, Time: time.Date(2025, 1, 1, 10, 0, 20, 0, time.UTC)},
+		{Price: decimal.NewFromInt(101), Size: decimal.NewFromInt(4), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 40, 0, time.UTC)},
+	}
+
+	tt := []struct {
+		name      string
+		processor bartender.Processor
+		wantEnd   time.Time
+	}{
+		{"Tick Bar Ends with its Last Trade", tick, time.Date(2025, 1, 1, 10, 0, 40, 0, time.UTC)},
+		{"Time Bar Ends with its Interval", timed, time.Date(2025, 1, 1, 10, 1, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			bars, err := bartender.Generate(trades, tc.processor)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			if len(bars) != 1 {
+				t.Fatalf("Generate() = %d bars, want 1", len(bars))
+			}
+
+			bar := bars[0]
+
+			if want := decimal.NewFromInt(807); !bar.Notional.Equal(want) {
+				t.Errorf("Notional = %v, want %v", bar.Notional, want)
+			}
+
+			if want := decimal.RequireFromString("100.875"); !bar.VWAP.Equal(want) {
+				t.Errorf("VWAP = %v, want %v", bar.VWAP, want)
+			}
+
+			if bar.Upticks != 2 || bar.Downticks != 1 {
+				t.Errorf("Upticks, Downticks = %d, %d, want 2, 1", bar.Upticks, bar.Downticks)
+			}
+
+			if want := trades[3].Time; !bar.LastTradeTime.Equal(want) {
+				t.Errorf("LastTradeTime = %v, want %v", bar.LastTradeTime, want)
+			}
+
+			if !bar.End.Equal(tc.wantEnd) {
+				t.Errorf("End = %v, want %v", bar.End, tc.wantEnd)
+			}
+		})
+	}
+}
+
+func TestBar_CSV(t *testing.T) {
+	bar := bartender.Bar{
+		Symbol:        "AAPL",
+		Open:          decimal.NewFromInt(100),
+		High:          decimal.NewFromInt(102),
+		Low:           decimal.NewFromInt(99),
+		Close:         decimal.NewFromInt(101),
+		Volume:        decimal.NewFromInt(8),
+		Start:         time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+		End:           time.Date(2025, 1, 1, 10, 1, 0, 0, time.UTC),
+		BuyVolume:     decimal.NewFromInt(7),
+		SellVolume:    decimal.NewFromInt(1),
+		Ticks:         4,
+		Upticks:       2,
+		Downticks:     1,
+		VWAP:          decimal.RequireFromString("100.88"),
+		Notional:      decimal.NewFromInt(807),
+		LastTradeTime: time.Date(2025, 1, 1, 10, 0, 40, 0, time.UTC),
+	}
+
+	record, err := bar.MarshalCSV()
+	if err != nil {
+		t.Fatalf("MarshalCSV() error = %v", err)
+	}
+
+	tt := []struct {
+		name    string
+		record  []string
+		want    bartender.Bar
+		wantErr bool
+	}{
+		{name: "Round Trip", record: record, want: bar},
+		{
+			name:   "Without Trade Statistics",
+			record: record[:11],
+			want: func() bartender.Bar {
+				b := bar
+				b.End, b.Downticks, b.VWAP, b.Notional, b.LastTradeTime = time.Time{}, 0, decimal.Zero, decimal.Zero, time.Time{}
+				return b
+			}(),
+		},
+		{
+			name:   "Without Metadata",
+			record: record[:16],
+			want:   bar,
+		},
+		{name: "Missing Columns", record: record[:10], wantErr: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var got bartender.Bar
+			if err := got.UnmarshalCSV(tc.record); (err != nil) != tc.wantErr {
+				t.Fatalf("UnmarshalCSV() error = %v, wantErr %v", err, tc.wantErr)
+			}
+
+			if tc.wantErr {
+				return
+			}
+
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreUnexported(bartender.Bar{})); diff != "" {
+				t.Errorf("UnmarshalCSV() (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBar_CSVRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	// price returns an arbitrary decimal of up to 18 decimal places, as for crypto and FX pairs
+	price := func() decimal.Decimal {
+		return decimal.New(rng.Int64N(1e15), -rng.Int32N(19))
+	}
+
+	zones := []*time.Location{time.UTC, time.FixedZone("EST", -5*60*60)}
+	timestamp := func() time.Time {
+		return time.Unix(0, rng.Int64N(4e18)).In(zones[rng.IntN(len(zones))])
+	}
+
+	reasons := []bartender.CloseReason{"", bartender.CloseReasonThreshold, bartender.CloseReasonEndOfStream}
+
+	for range 1000 {
+		want := bartender.Bar{
+			Symbol:        "BTC/USD",
+			Open:          price(),
+			High:          price(),
+			Low:           price(),
+			Close:         price(),
+			Volume:        price(),
+			Start:         timestamp(),
+			End:           timestamp(),
+			BuyVolume:     price(),
+			SellVolume:    price(),
+			Ticks:         rng.IntN(1000),
+			Upticks:       rng.IntN(1000),
+			Downticks:     rng.IntN(1000),
+			VWAP:          price(),
+			Notional:      price(),
+			LastTradeTime: timestamp(),
+			Threshold:     price(),
+			Provisional:   rng.IntN(2) == 0,
+			Revision:      rng.IntN(3),
+			Processor:     "DollarBarConfig",
+			Sequence:      rng.Int64(),
+			CloseReason:   reasons[rng.IntN(len(reasons))],
+		}
+
+		record, err := want.MarshalCSV()
+		if err != nil {
+			t.Fatalf("MarshalCSV() error = %v", err)
+		}
+
+		var got bartender.Bar
+		if err := got.UnmarshalCSV(record); err != nil {
+			t.Fatalf("UnmarshalCSV(%v) error = %v", record, err)
+		}
+
+		if diff := cmp.Diff(want, got, cmpopts.IgnoreUnexported(bartender.Bar{})); diff != "" {
+			t.Fatalf("UnmarshalCSV(MarshalCSV()) (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestBar_Close(t *testing.T) {
+	tick, err := bartender.New(bartender.WithTickThreshold(2))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	timed, err := bartender.New(bartender.WithInterval(time.Minute))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	trade := func(t time.Time) bartender.Trade {
+		return bartender.Trade{Symbol: "AAPL", Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: t}
+	}
+
+	type closed struct {
+		Processor   string
+		Sequence    int64
+		CloseReason bartender.CloseReason
+	}
+
+	tt := []struct {
+		name      string
+		processor bartender.Processor
+		trades    []bartender.Trade
+		want      []closed
+	}{
+		{
+			name:      "Tick Bars",
+			processor: tick,
+			trades: []bartender.Trade{
+				trade(time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)),
+				trade(time.Date(2025, 1, 1, 10, 1, 0, 0, time.UTC)),
+				trade(time.Date(2025, 1, 1, 10, 2, 0, 0, time.UTC)),
+				trade(time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)),
+				trade(time.Date(2025, 1, 2, 10, 1, 0, 0, time.UTC)),
+				trade(time.Date(2025, 1, 2, 10, 2, 0, 0, time.UTC)),
+			},
+			want: []closed{
+				{"TickBarConfig", 1, bartender.CloseReasonThreshold},
+				{"TickBarConfig", 2, bartender.CloseReasonSession},
+				{"TickBarConfig", 3, bartender.CloseReasonThreshold},
+				{"TickBarConfig", 4, bartender.CloseReasonEndOfStream},
+			},
+		},
+		{
+			name:      "Time Bars",
+			processor: timed,
+			trades: []bartender.Trade{
+				trade(time.Date(2025, 1, 1, 10, 0, 10, 0, time.UTC)),
+				trade(time.Date(2025, 1, 1, 10, 1, 10, 0, time.UTC)),
+				trade(time.Date(2025, 1, 1, 10, 3, 10, 0, time.UTC)),
+			},
+			want: []closed{
+				{"TimeBarConfig", 1, bartender.CloseReasonInterval},
+				{"TimeBarConfig", 2, bartender.CloseReasonInterval},
+				{"TimeBarConfig", 3, bartender.CloseReasonInterval},
+				{"TimeBarConfig", 4, bartender.CloseReasonEndOfStream},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			bars, err := bartender.Generate(tc.trades, tc.processor)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			got := make([]closed, 0, len(bars))
+			for _, bar := range bars {
+				got = append(got, closed{bar.Processor, bar.Sequence, bar.CloseReason})
+			}
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Generate() (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
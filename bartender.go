@@ -8,7 +8,11 @@ Licensed under the MIT license. See LICENSE file in the project root for details
 package bartender
 
 import (
+	"context"
 	"fmt"
+	"iter"
+	"reflect"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -22,9 +26,38 @@ type Processor interface {
 	Process(<-chan Trade) chan *Bar
 }
 
+// ContextProcessor is a Processor that can be stopped before its Trade channel is closed.
+type ContextProcessor interface {
+	Processor
+
+	// ProcessContext behaves like Process, but stops processing and closes the resulting Bar channel once the
+	// context is cancelled. Bars that are still in progress when the context is cancelled are discarded.
+	ProcessContext(context.Context, <-chan Trade) chan *Bar
+}
+
 type Option[T any] func(*T)
 
 func New[T Processor](options ...Option[T]) (Processor, error) {
+	cfg, err := configure(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// NewQuoteProcessor creates a QuoteProcessor from the provided options.
+func NewQuoteProcessor[T QuoteProcessor](options ...Option[T]) (QuoteProcessor, error) {
+	cfg, err := configure(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// configure applies the options to a new configuration and validates it.
+func configure[T any](options ...Option[T]) (T, error) {
 	var cfg T
 
 	validate := validator.New()
@@ -34,67 +67,322 @@ func New[T Processor](options ...Option[T]) (Processor, error) {
 	}
 
 	if err := validate.Struct(cfg); err != nil {
-		return nil, err
+		return cfg, err
+	}
+
+	if c, ok := any(&cfg).(interface{ check() error }); ok {
+		if err := c.check(); err != nil {
+			return cfg, err
+		}
+	}
+
+	if p, ok := any(&cfg).(interface{ bind(string) error }); ok {
+		if err := p.bind(reflect.TypeOf(cfg).Name()); err != nil {
+			return cfg, err
+		}
 	}
 
 	return cfg, nil
 }
 
+// processorConfig holds the options shared by every Processor configuration.
+type processorConfig struct {
+	session    SessionBoundary
+	checkpoint *Checkpoint
+
+	partialBars     bool
+	partialThrottle time.Duration
+
+	features bool
+
+	// name of the configuration type embedding processorConfig, set by New
+	name string
+
+	// last sequence number closed for each symbol during a run of the processor, set by restore
+	sequences map[string]int64
+}
+
+// bind records the name of the configuration and checks that its checkpoint was saved by the same type of processor.
+func (c *processorConfig) bind(name string) error {
+	c.name = name
+
+	if c.checkpoint != nil {
+		return c.checkpoint.check(name)
+	}
+
+	return nil
+}
+
+// closeBar stamps the bar with the processor, the next sequence number for its symbol and the reason it closed, then
+// sends it. It returns false if the context was cancelled before the bar was sent.
+func (c processorConfig) closeBar(ctx context.Context, output chan<- *Bar, bar *Bar, reason CloseReason) bool {
+	c.sequences[bar.Symbol]++
+
+	bar.Processor = c.name
+	bar.Sequence = c.sequences[bar.Symbol]
+	bar.CloseReason = reason
+
+	return send(ctx, output, bar)
+}
+
+// configurable is implemented by pointers to Processor configurations that embed processorConfig.
+type configurable[T any] interface {
+	*T
+	setSessionBoundary(SessionBoundary)
+	setCheckpoint(*Checkpoint)
+	setPartialBars(time.Duration)
+	setFeatures()
+}
+
 // Generate processes trades synchronously. It accepts all trades to process and returns all bars generated from
-// the provided trades.
+// the provided trades. If a processor stops with an error, the bars generated before it stopped are returned along
+// with the error.
 func Generate(trades []Trade, processor Processor, filters ...FilterFunc) ([]Bar, error) {
 	if len(trades) == 0 {
 		return nil, fmt.Errorf("no trades provided")
 	}
 
+	ctx, cancel := withAbort(context.Background())
+	defer cancel(nil)
+
 	bars := make([]Bar, 0, len(trades))
 	tradesCh := make(chan Trade)
 
 	go func() {
 		defer close(tradesCh)
 		for _, trade := range trades {
-			tradesCh <- trade
+			if !send(ctx, tradesCh, trade) {
+				return
+			}
 		}
 	}()
 
 	// apply the filter to the trades channel
 	filteredTradesStream := tradesCh
 	for _, f := range filters {
-		filteredTradesStream = Filter(f)(filteredTradesStream)
+		filteredTradesStream = FilterContext(ctx, f)(filteredTradesStream)
 	}
 
-	for bar := range processor.Process(filteredTradesStream) {
+	for bar := range process(ctx, processor, filteredTradesStream) {
 		if bar != nil {
 			bars = append(bars, *bar)
 		}
 	}
 
+	// was the stream aborted by the processor?
+	if ctx.Err() != nil {
+		return bars, context.Cause(ctx)
+	}
+
 	return bars, nil
 }
 
 // GenerateStream processes a channel of trades and returns completed bars on the response channel.
 func GenerateStream(trades chan Trade, processor Processor, filters ...FilterFunc) (<-chan Bar, error) {
+	bars, _, err := GenerateStreamContext(context.Background(), trades, processor, filters...)
+
+	return bars, err
+}
+
+// GenerateStreamContext processes a channel of trades and returns completed bars on the response channel until the
+// trades channel is closed or the context is cancelled.
+//
+// When the context is cancelled, or a processor stops the stream with an error, the filter chain and processor are
+// torn down, the bar channel is closed and the cause is sent on the error channel. The error channel is closed once the
+// bar channel has been closed.
+func GenerateStreamContext(ctx context.Context, trades chan Trade, processor Processor, filters ...FilterFunc) (<-chan Bar, <-chan error, error) {
 	if trades == nil {
-		return nil, fmt.Errorf("trades channel is nil")
+		return nil, nil, fmt.Errorf("trades channel is nil")
 	}
 
 	bars := make(chan Bar)
+	errs := make(chan error, 1)
+
+	ctx, cancel := withAbort(ctx)
 
 	go func(trades chan Trade) {
+		defer close(errs)
 		defer close(bars)
+		defer cancel(nil)
 
 		// apply the filter to the trades channel
 		filteredTradesStream := trades
 		for _, f := range filters {
-			filteredTradesStream = Filter(f)(filteredTradesStream)
+			filteredTradesStream = FilterContext(ctx, f)(filteredTradesStream)
 		}
 
-		for bar := range processor.Process(filteredTradesStream) {
+		for bar := range process(ctx, processor, filteredTradesStream) {
 			if bar != nil {
-				bars <- *bar
+				send(ctx, bars, *bar)
 			}
 		}
+
+		if ctx.Err() != nil {
+			errs <- context.Cause(ctx)
+		}
 	}(trades)
 
-	return bars, nil
+	return bars, errs, nil
+}
+
+// GenerateQuotes processes quotes synchronously. It accepts all quotes to process and returns all bars generated from
+// the provided quotes.
+func GenerateQuotes(quotes []Quote, processor QuoteProcessor) ([]QuoteBar, error) {
+	if len(quotes) == 0 {
+		return nil, fmt.Errorf("no quotes provided")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	quotesCh := make(chan Quote)
+
+	go func() {
+		defer close(quotesCh)
+		for _, quote := range quotes {
+			if !send(ctx, quotesCh, quote) {
+				return
+			}
+		}
+	}()
+
+	stream, errs, err := GenerateQuoteStreamContext(ctx, quotesCh, processor)
+	if err != nil {
+		return nil, err
+	}
+
+	bars := make([]QuoteBar, 0, len(quotes))
+	for bar := range stream {
+		bars = append(bars, bar)
+	}
+
+	return bars, <-errs
+}
+
+// GenerateQuoteStream processes a channel of quotes and returns completed bars on the response channel.
+func GenerateQuoteStream(quotes chan Quote, processor QuoteProcessor) (<-chan QuoteBar, error) {
+	bars, _, err := GenerateQuoteStreamContext(context.Background(), quotes, processor)
+
+	return bars, err
+}
+
+// GenerateQuoteStreamContext processes a channel of quotes and returns completed bars on the response channel until
+// the quotes channel is closed or the context is cancelled.
+//
+// When the context is cancelled, the processor is torn down, the bar channel is closed and the cause is sent on the
+// error channel. The error channel is closed once the bar channel has been closed.
+func GenerateQuoteStreamContext(ctx context.Context, quotes chan Quote, processor QuoteProcessor) (<-chan QuoteBar, <-chan error, error) {
+	if quotes == nil {
+		return nil, nil, fmt.Errorf("quotes channel is nil")
+	}
+
+	bars := make(chan QuoteBar)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(bars)
+
+		for bar := range processor.ProcessQuotes(ctx, quotes) {
+			if bar != nil {
+				send(ctx, bars, *bar)
+			}
+		}
+
+		if ctx.Err() != nil {
+			errs <- context.Cause(ctx)
+		}
+	}()
+
+	return bars, errs, nil
+}
+
+// process runs the processor until the trades channel is closed or the context is cancelled. Processors that do
+// not implement ContextProcessor are stopped by closing their input once the context is cancelled.
+func process(ctx context.Context, processor Processor, trades <-chan Trade) chan *Bar {
+	if p, ok := processor.(ContextProcessor); ok {
+		return p.ProcessContext(ctx, trades)
+	}
+
+	input := make(chan Trade)
+
+	go func() {
+		defer close(input)
+
+		for trade := range receive(ctx, trades) {
+			if !send(ctx, input, trade) {
+				return
+			}
+		}
+	}()
+
+	return processor.Process(input)
+}
+
+// abortKey is the context key of the function that stops a stream with an error.
+type abortKey struct{}
+
+// withAbort returns a context that processors running in it can cancel with an error by calling abort.
+func withAbort(ctx context.Context) (context.Context, context.CancelCauseFunc) {
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	return context.WithValue(ctx, abortKey{}, cancel), cancel
+}
+
+// abort stops the stream the processor is running in with err, reporting false if the context was not created by
+// withAbort.
+func abort(ctx context.Context, err error) bool {
+	cancel, ok := ctx.Value(abortKey{}).(context.CancelCauseFunc)
+	if ok {
+		cancel(err)
+	}
+
+	return ok
+}
+
+// newTrades yields the new trades from the channel until it is closed or the context is cancelled, skipping cancels
+// and corrections.
+func (c processorConfig) newTrades(ctx context.Context, trades <-chan Trade) iter.Seq[Trade] {
+	return func(yield func(Trade) bool) {
+		for trade := range receive(ctx, trades) {
+			if trade.Action != "" {
+				c.skip(trade)
+				continue
+			}
+
+			if !yield(trade) {
+				return
+			}
+		}
+	}
+}
+
+// receive yields values from the channel until it is closed or the context is cancelled.
+func receive[T any](ctx context.Context, ch <-chan T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for ctx.Err() == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-ch:
+				if !ok || !yield(v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// send delivers the value on the channel, reporting false if the context was cancelled first.
+func send[T any](ctx context.Context, ch chan<- T, v T) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	select {
+	case ch <- v:
+		return true
+	case <-ctx.Done():
+		return false
+	}
 }
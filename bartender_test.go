@@ -8,13 +8,22 @@ Licensed under the MIT license. See LICENSE file in the project root for details
 package bartender_test
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
+	decimal "github.com/alpacahq/alpacadecimal"
 	"github.com/csgriffis/bartender"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 )
 
+// ignoreBarStats ignores the bar fields derived from every trade and the metadata of closed bars, which the
+// handwritten bars of most tests leave unset. TestBar_Statistics and TestBar_Close cover them.
+var ignoreBarStats = cmpopts.IgnoreFields(bartender.Bar{},
+	"End", "Downticks", "VWAP", "Notional", "LastTradeTime", "Processor", "Sequence", "CloseReason")
+
 type TestCase[T any] struct {
 	name    string
 	input   T
@@ -45,8 +54,129 @@ func (tc TestCase[T]) Run(t *testing.T, p bartender.Processor) {
 			barsGot = append(barsGot, bar)
 		}
 
-		if diff := cmp.Diff(barsGot, tc.want, cmpopts.IgnoreUnexported(bartender.Bar{})); diff != "" {
+		if diff := cmp.Diff(barsGot, tc.want, cmpopts.IgnoreUnexported(bartender.Bar{}), ignoreBarStats); diff != "" {
 			t.Errorf("GenerateStream() = %+v, want %+v", barsGot, tc.want)
 		}
 	})
 }
+
+// passthroughProcessor emits a bar per trade and does not implement bartender.ContextProcessor.
+type passthroughProcessor struct{}
+
+func (passthroughProcessor) Process(trades <-chan bartender.Trade) chan *bartender.Bar {
+	output := make(chan *bartender.Bar)
+
+	go func() {
+		defer close(output)
+
+		for trade := range trades {
+			output <- &bartender.Bar{Open: trade.Price, Close: trade.Price, Start: trade.Time}
+		}
+	}()
+
+	return output
+}
+
+func TestGenerateStreamContext(t *testing.T) {
+	tick, err := bartender.New(bartender.WithTickThreshold(1))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tt := []struct {
+		name      string
+		processor bartender.Processor
+		filters   []bartender.FilterFunc
+	}{
+		{name: "Context Processor", processor: tick},
+		{name: "Plain Processor", processor: passthroughProcessor{}},
+		{name: "With Filter", processor: tick, filters: []bartender.FilterFunc{func(bartender.Trade) bool { return true }}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			// an endless trade stream that is never closed
+			tradesChan := make(chan bartender.Trade)
+			go func() {
+				for i := 0; ; i++ {
+					trade := bartender.Trade{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, i, 0, time.UTC)}
+					select {
+					case tradesChan <- trade:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}()
+
+			barCh, errCh, err := bartender.GenerateStreamContext(ctx, tradesChan, tc.processor, tc.filters...)
+			if err != nil {
+				t.Fatalf("GenerateStreamContext() error = %v", err)
+			}
+
+			for range 3 {
+				<-barCh
+			}
+			cancel()
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				for range barCh {
+				}
+			}()
+
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("bar channel was not closed after cancellation")
+			}
+
+			if err := <-errCh; !errors.Is(err, context.Canceled) {
+				t.Errorf("GenerateStreamContext() stream error = %v, want %v", err, context.Canceled)
+			}
+		})
+	}
+}
+
+func TestGenerateStreamContext_Completed(t *testing.T) {
+	p, err := bartender.New(bartender.WithTickThreshold(1))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tradesChan := make(chan bartender.Trade, 1)
+	tradesChan <- bartender.Trade{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)}
+	close(tradesChan)
+
+	barCh, errCh, err := bartender.GenerateStreamContext(context.Background(), tradesChan, p)
+	if err != nil {
+		t.Fatalf("GenerateStreamContext() error = %v", err)
+	}
+
+	var bars int
+	for range barCh {
+		bars++
+	}
+
+	if bars != 1 {
+		t.Errorf("GenerateStreamContext() bars = %d, want 1", bars)
+	}
+
+	if err, ok := <-errCh; ok {
+		t.Errorf("GenerateStreamContext() stream error = %v, want closed channel", err)
+	}
+}
+
+func TestGenerateStreamContext_NilTrades(t *testing.T) {
+	p, err := bartender.New(bartender.WithTickThreshold(1))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if _, _, err := bartender.GenerateStreamContext(context.Background(), nil, p); err == nil {
+		t.Error("GenerateStreamContext() error = nil, want error")
+	}
+}
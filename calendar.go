@@ -0,0 +1,516 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type SessionKind string
+
+const (
+	SessionRegular  SessionKind = "regular"
+	SessionExtended SessionKind = "extended"
+)
+
+// Calendar describes when an exchange is open: its sessions, holidays and early closes. Sessions are attributed to
+// the trading date on which they close, so a session that opens in the evening belongs to the following date.
+//
+// Calendars are loaded from JSON or YAML with LoadCalendar, or built in with USEquities and CME.
+type Calendar struct {
+	Name string `json:"name" yaml:"name"`
+	// TimeZone is the IANA name of the location session times are given in, defaults to UTC.
+	TimeZone    string       `json:"timezone" yaml:"timezone"`
+	Sessions    []Session    `json:"sessions" yaml:"sessions"`
+	Holidays    []Date       `json:"holidays,omitempty" yaml:"holidays,omitempty"`
+	EarlyCloses []EarlyClose `json:"early_closes,omitempty" yaml:"early_closes,omitempty"`
+
+	once        sync.Once
+	location    *time.Location
+	holidays    map[Date]bool
+	earlyCloses map[Date][]EarlyClose
+}
+
+// Session is a trading window. When Close is not after Open the session opens on the day before its trading date.
+type Session struct {
+	Kind  SessionKind `json:"kind" yaml:"kind"`
+	Open  TimeOfDay   `json:"open" yaml:"open"`
+	Close TimeOfDay   `json:"close" yaml:"close"`
+	// Days are the weekdays of the trading dates the session runs on, defaults to Monday through Friday.
+	Days []Weekday `json:"days,omitempty" yaml:"days,omitempty"`
+}
+
+// EarlyClose shortens the sessions of the given kind on a date, or every session when Kind is empty.
+type EarlyClose struct {
+	Date  Date        `json:"date" yaml:"date"`
+	Close TimeOfDay   `json:"close" yaml:"close"`
+	Kind  SessionKind `json:"kind,omitempty" yaml:"kind,omitempty"`
+}
+
+// LoadCalendar reads a calendar from a JSON or YAML file, chosen by the file extension.
+func LoadCalendar(path string) (*Calendar, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open calendar: %w", err)
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return ReadCalendarJSON(f)
+	case ".yaml", ".yml":
+		return ReadCalendarYAML(f)
+	}
+
+	return nil, fmt.Errorf("unsupported calendar format: %s", path)
+}
+
+// ReadCalendarJSON reads a calendar in JSON format.
+func ReadCalendarJSON(r io.Reader) (*Calendar, error) {
+	var c Calendar
+	if err := json.NewDecoder(r).Decode(&c); err != nil {
+		return nil, fmt.Errorf("failed to decode calendar: %w", err)
+	}
+
+	return &c, c.validate()
+}
+
+// ReadCalendarYAML reads a calendar in YAML format.
+func ReadCalendarYAML(r io.Reader) (*Calendar, error) {
+	var c Calendar
+	if err := yaml.NewDecoder(r).Decode(&c); err != nil {
+		return nil, fmt.Errorf("failed to decode calendar: %w", err)
+	}
+
+	return &c, c.validate()
+}
+
+func (c *Calendar) validate() error {
+	if _, err := time.LoadLocation(c.TimeZone); err != nil {
+		return fmt.Errorf("invalid calendar timezone: %w", err)
+	}
+
+	if len(c.Sessions) == 0 {
+		return fmt.Errorf("calendar %q has no sessions", c.Name)
+	}
+
+	return nil
+}
+
+// index resolves the location and builds the holiday and early close lookups on first use. An invalid TimeZone
+// falls back to UTC.
+func (c *Calendar) index() {
+	c.once.Do(func() {
+		location, err := time.LoadLocation(c.TimeZone)
+		if err != nil {
+			location = time.UTC
+		}
+
+		c.location = location
+		c.holidays = make(map[Date]bool, len(c.Holidays))
+		c.earlyCloses = make(map[Date][]EarlyClose, len(c.EarlyCloses))
+
+		for _, d := range c.Holidays {
+			c.holidays[d] = true
+		}
+
+		for _, e := range c.EarlyCloses {
+			c.earlyCloses[e.Date] = append(c.earlyCloses[e.Date], e)
+		}
+	})
+}
+
+// Location returns the location session times are given in.
+func (c *Calendar) Location() *time.Location {
+	c.index()
+
+	return c.location
+}
+
+// IsOpen reports whether t falls in a session of one of the given kinds, or of any kind when none are given.
+func (c *Calendar) IsOpen(t time.Time, kinds ...SessionKind) bool {
+	_, _, ok := c.SessionAt(t, kinds...)
+
+	return ok
+}
+
+// SessionAt returns the trading date and session containing t, limited to the given kinds when any are given.
+func (c *Calendar) SessionAt(t time.Time, kinds ...SessionKind) (Date, Session, bool) {
+	c.index()
+
+	// a session containing t closes on the date of t or, for sessions that open the evening before, the next date
+	today := DateOf(t.In(c.location))
+
+	for _, date := range []Date{today, today.AddDays(1)} {
+		if c.holidays[date] {
+			continue
+		}
+
+		for _, s := range c.Sessions {
+			if !matchesKind(s.Kind, kinds) || !s.tradesOn(date.Weekday()) {
+				continue
+			}
+
+			open, closing := c.bounds(date, s)
+			if !t.Before(open) && t.Before(closing) {
+				return date, s, true
+			}
+		}
+	}
+
+	return Date{}, Session{}, false
+}
+
+// bounds returns when the session opens and closes for the trading date, taking early closes into account.
+func (c *Calendar) bounds(date Date, s Session) (time.Time, time.Time) {
+	openDate := date
+	if s.Close <= s.Open {
+		openDate = date.AddDays(-1)
+	}
+
+	open := openDate.at(s.Open, c.location)
+	closing := date.at(s.Close, c.location)
+
+	for _, e := range c.earlyCloses[date] {
+		if e.Kind != "" && e.Kind != s.Kind {
+			continue
+		}
+
+		if early := date.at(e.Close, c.location); early.Before(closing) {
+			closing = early
+		}
+	}
+
+	return open, closing
+}
+
+func (s Session) tradesOn(day time.Weekday) bool {
+	if len(s.Days) == 0 {
+		return day != time.Saturday && day != time.Sunday
+	}
+
+	for _, d := range s.Days {
+		if time.Weekday(d) == day {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchesKind(kind SessionKind, kinds []SessionKind) bool {
+	if len(kinds) == 0 {
+		return true
+	}
+
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Date is a calendar date, formatted as 2006-01-02.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// DateOf returns the date of t in its location.
+func DateOf(t time.Time) Date {
+	year, month, day := t.Date()
+
+	return Date{Year: year, Month: month, Day: day}
+}
+
+// AddDays returns the date n days after d.
+func (d Date) AddDays(n int) Date {
+	return DateOf(time.Date(d.Year, d.Month, d.Day+n, 0, 0, 0, 0, time.UTC))
+}
+
+func (d Date) Weekday() time.Weekday {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, time.UTC).Weekday()
+}
+
+// at returns the time of day on the date in the location.
+func (d Date) at(offset TimeOfDay, location *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, int(offset), location)
+}
+
+func (d Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Date) UnmarshalText(text []byte) error {
+	t, err := time.Parse(time.DateOnly, string(text))
+	if err != nil {
+		return fmt.Errorf("failed to parse date: %w", err)
+	}
+
+	*d = DateOf(t)
+
+	return nil
+}
+
+// TimeOfDay is an offset from midnight, formatted as 15:04 or 15:04:05. 24:00 marks the end of the day.
+type TimeOfDay time.Duration
+
+// At returns the time of day for the hour and minute.
+func At(hour, minute int) TimeOfDay {
+	return TimeOfDay(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+}
+
+func (t TimeOfDay) String() string {
+	d := time.Duration(t)
+	if seconds := d % time.Minute; seconds != 0 {
+		return fmt.Sprintf("%02d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(seconds.Seconds()))
+	}
+
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
+
+func (t TimeOfDay) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *TimeOfDay) UnmarshalText(text []byte) error {
+	var hour, minute, second int
+
+	parts := strings.Split(string(text), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return fmt.Errorf("failed to parse time of day: %q", text)
+	}
+
+	for i, field := range []*int{&hour, &minute, &second}[:len(parts)] {
+		v, err := strconv.Atoi(parts[i])
+		if err != nil || v < 0 {
+			return fmt.Errorf("failed to parse time of day: %q", text)
+		}
+
+		*field = v
+	}
+
+	d := time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute + time.Duration(second)*time.Second
+	if minute > 59 || second > 59 || d > 24*time.Hour {
+		return fmt.Errorf("time of day out of range: %q", text)
+	}
+
+	*t = TimeOfDay(d)
+
+	return nil
+}
+
+// Weekday is a day of the week, formatted by its English name.
+type Weekday time.Weekday
+
+func (d Weekday) MarshalText() ([]byte, error) {
+	return []byte(strings.ToLower(time.Weekday(d).String())), nil
+}
+
+func (d *Weekday) UnmarshalText(text []byte) error {
+	name := strings.ToLower(string(text))
+
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if full := strings.ToLower(day.String()); name == full || name == full[:3] {
+			*d = Weekday(day)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("failed to parse weekday: %q", text)
+}
+
+// calendarYears is the range of years the built-in calendars generate holidays for.
+const (
+	calendarFirstYear = 2000
+	calendarLastYear  = 2050
+)
+
+// USEquities returns the NYSE and Nasdaq calendar: the regular session from 09:30 to 16:00 and the extended session
+// from 04:00 to 20:00 New York time, closed on exchange holidays and closing early at 13:00 (17:00 for the extended
+// session) on the trading days before Independence Day and Christmas and after Thanksgiving. Holidays are generated
+// from the exchange rules for 2000 through 2050; unscheduled closures are not included.
+func USEquities() *Calendar {
+	c := &Calendar{
+		Name:     "us-equities",
+		TimeZone: "America/New_York",
+		Sessions: []Session{
+			{Kind: SessionRegular, Open: At(9, 30), Close: At(16, 0)},
+			{Kind: SessionExtended, Open: At(4, 0), Close: At(20, 0)},
+		},
+	}
+
+	for year := calendarFirstYear; year <= calendarLastYear; year++ {
+		h := usHolidaysFor(year)
+
+		c.Holidays = append(c.Holidays, h.newYears, h.mlk, h.presidents, h.goodFriday, h.memorial, h.independence,
+			h.labor, h.thanksgiving, h.christmas)
+		if year >= 2022 {
+			c.Holidays = append(c.Holidays, h.juneteenth)
+		}
+
+		for _, d := range h.earlyCloses() {
+			c.EarlyCloses = append(c.EarlyCloses,
+				EarlyClose{Date: d, Close: At(13, 0), Kind: SessionRegular},
+				EarlyClose{Date: d, Close: At(17, 0), Kind: SessionExtended},
+			)
+		}
+	}
+
+	return c
+}
+
+// CME returns an approximation of the CME Globex calendar for equity index futures: a session from 17:00 Chicago
+// time on the previous day to 16:00, closed on New Year's Day, Good Friday and Christmas, halting at 12:00 on the
+// other US holidays and at 12:15 after Thanksgiving and on Christmas Eve. Verify against the exchange's holiday
+// calendar for other products.
+func CME() *Calendar {
+	c := &Calendar{
+		Name:     "cme",
+		TimeZone: "America/Chicago",
+		Sessions: []Session{
+			{Kind: SessionRegular, Open: At(17, 0), Close: At(16, 0)},
+		},
+	}
+
+	for year := calendarFirstYear; year <= calendarLastYear; year++ {
+		h := usHolidaysFor(year)
+
+		c.Holidays = append(c.Holidays, h.newYears, h.goodFriday, h.christmas)
+
+		halts := []Date{h.mlk, h.presidents, h.memorial, h.independence, h.labor, h.thanksgiving}
+		if year >= 2022 {
+			halts = append(halts, h.juneteenth)
+		}
+
+		for _, d := range halts {
+			c.EarlyCloses = append(c.EarlyCloses, EarlyClose{Date: d, Close: At(12, 0)})
+		}
+
+		for _, d := range []Date{h.thanksgiving.AddDays(1), {Year: year, Month: time.December, Day: 24}} {
+			if d.Weekday() != time.Saturday && d.Weekday() != time.Sunday && d != h.christmas {
+				c.EarlyCloses = append(c.EarlyCloses, EarlyClose{Date: d, Close: At(12, 15)})
+			}
+		}
+	}
+
+	return c
+}
+
+// usHolidays are the observed dates of the US exchange holidays in a year.
+type usHolidays struct {
+	year                                            int
+	newYears, mlk, presidents, goodFriday, memorial Date
+	juneteenth, independence, labor, thanksgiving   Date
+	christmas                                       Date
+}
+
+func usHolidaysFor(year int) usHolidays {
+	easter := easterSunday(year)
+
+	return usHolidays{
+		year:         year,
+		newYears:     observedNewYears(year),
+		mlk:          nthWeekday(year, time.January, time.Monday, 3),
+		presidents:   nthWeekday(year, time.February, time.Monday, 3),
+		goodFriday:   easter.AddDays(-2),
+		memorial:     lastWeekday(year, time.May, time.Monday),
+		juneteenth:   observed(Date{Year: year, Month: time.June, Day: 19}),
+		independence: observed(Date{Year: year, Month: time.July, Day: 4}),
+		labor:        nthWeekday(year, time.September, time.Monday, 1),
+		thanksgiving: nthWeekday(year, time.November, time.Thursday, 4),
+		christmas:    observed(Date{Year: year, Month: time.December, Day: 25}),
+	}
+}
+
+// earlyCloses returns the trading days before Independence Day and Christmas and after Thanksgiving.
+func (h usHolidays) earlyCloses() []Date {
+	closes := []Date{h.thanksgiving.AddDays(1)}
+
+	for _, d := range []Date{{Year: h.year, Month: time.July, Day: 3}, {Year: h.year, Month: time.December, Day: 24}} {
+		// only when the eve is a trading day that is not itself the observed holiday
+		if day := d.Weekday(); day >= time.Monday && day <= time.Thursday {
+			closes = append(closes, d)
+		}
+	}
+
+	return closes
+}
+
+// observed moves a holiday on a Saturday to the Friday before and on a Sunday to the Monday after.
+func observed(d Date) Date {
+	switch d.Weekday() {
+	case time.Saturday:
+		return d.AddDays(-1)
+	case time.Sunday:
+		return d.AddDays(1)
+	}
+
+	return d
+}
+
+// observedNewYears follows the NYSE rule of not observing New Year's Day on the preceding Friday.
+func observedNewYears(year int) Date {
+	d := Date{Year: year, Month: time.January, Day: 1}
+	if d.Weekday() == time.Sunday {
+		return d.AddDays(1)
+	}
+
+	return d
+}
+
+func nthWeekday(year int, month time.Month, day time.Weekday, n int) Date {
+	first := Date{Year: year, Month: month, Day: 1}
+	offset := (int(day) - int(first.Weekday()) + 7) % 7
+
+	return first.AddDays(offset + 7*(n-1))
+}
+
+func lastWeekday(year int, month time.Month, day time.Weekday) Date {
+	last := Date{Year: year, Month: month + 1, Day: 1}.AddDays(-1)
+	offset := (int(last.Weekday()) - int(day) + 7) % 7
+
+	return last.AddDays(-offset)
+}
+
+// easterSunday computes the date of Easter using the anonymous Gregorian algorithm.
+func easterSunday(year int) Date {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+
+	return Date{Year: year, Month: time.Month(month), Day: day}
+}
@@ -0,0 +1,151 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	decimal "github.com/alpacahq/alpacadecimal"
+	"github.com/csgriffis/bartender"
+)
+
+func TestCalendar_IsOpen(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+
+	chicago, err := time.LoadLocation("America/Chicago")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+
+	tt := []struct {
+		name     string
+		calendar *bartender.Calendar
+		t        time.Time
+		kinds    []bartender.SessionKind
+		want     bool
+	}{
+		{name: "Regular Session", calendar: bartender.USEquities(), t: time.Date(2025, 1, 6, 10, 0, 0, 0, newYork), kinds: []bartender.SessionKind{bartender.SessionRegular}, want: true},
+		{name: "Pre-Market Outside Regular Session", calendar: bartender.USEquities(), t: time.Date(2025, 1, 6, 9, 0, 0, 0, newYork), kinds: []bartender.SessionKind{bartender.SessionRegular}, want: false},
+		{name: "Pre-Market in Extended Session", calendar: bartender.USEquities(), t: time.Date(2025, 1, 6, 9, 0, 0, 0, newYork), kinds: []bartender.SessionKind{bartender.SessionExtended}, want: true},
+		{name: "Any Session", calendar: bartender.USEquities(), t: time.Date(2025, 1, 6, 19, 0, 0, 0, newYork), want: true},
+		{name: "Weekend", calendar: bartender.USEquities(), t: time.Date(2025, 1, 4, 12, 0, 0, 0, newYork), want: false},
+		{name: "Good Friday", calendar: bartender.USEquities(), t: time.Date(2025, 4, 18, 12, 0, 0, 0, newYork), want: false},
+		{name: "Observed Independence Day", calendar: bartender.USEquities(), t: time.Date(2026, 7, 3, 12, 0, 0, 0, newYork), want: false},
+		{name: "Thanksgiving", calendar: bartender.USEquities(), t: time.Date(2025, 11, 27, 12, 0, 0, 0, newYork), want: false},
+		{name: "Before Early Close", calendar: bartender.USEquities(), t: time.Date(2025, 7, 3, 12, 59, 0, 0, newYork), kinds: []bartender.SessionKind{bartender.SessionRegular}, want: true},
+		{name: "After Early Close", calendar: bartender.USEquities(), t: time.Date(2025, 7, 3, 13, 0, 0, 0, newYork), kinds: []bartender.SessionKind{bartender.SessionRegular}, want: false},
+		{name: "Extended Session After Early Close", calendar: bartender.USEquities(), t: time.Date(2025, 11, 28, 16, 0, 0, 0, newYork), kinds: []bartender.SessionKind{bartender.SessionExtended}, want: true},
+		{name: "CME Sunday Open", calendar: bartender.CME(), t: time.Date(2025, 1, 5, 17, 30, 0, 0, chicago), want: true},
+		{name: "CME Daily Halt", calendar: bartender.CME(), t: time.Date(2025, 1, 7, 16, 30, 0, 0, chicago), want: false},
+		{name: "CME Friday Evening", calendar: bartender.CME(), t: time.Date(2025, 1, 10, 17, 30, 0, 0, chicago), want: false},
+		{name: "CME Holiday Halt", calendar: bartender.CME(), t: time.Date(2025, 1, 20, 12, 30, 0, 0, chicago), want: false},
+		{name: "CME Christmas", calendar: bartender.CME(), t: time.Date(2025, 12, 24, 18, 0, 0, 0, chicago), want: false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.calendar.IsOpen(tc.t, tc.kinds...); got != tc.want {
+				t.Errorf("IsOpen() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoadCalendar(t *testing.T) {
+	const jsonCalendar = `{
+		"name": "crypto",
+		"timezone": "UTC",
+		"sessions": [{"kind": "regular", "open": "00:00", "close": "24:00", "days": ["mon", "tue", "wed", "thu", "fri", "sat", "sun"]}],
+		"holidays": ["2025-12-25"]
+	}`
+
+	const yamlCalendar = `
+name: crypto
+timezone: UTC
+sessions:
+  - kind: regular
+    open: "00:00"
+    close: "24:00"
+    days: [monday, tuesday, wednesday, thursday, friday, saturday, sunday]
+holidays:
+  - 2025-12-25
+`
+
+	tt := []struct {
+		name    string
+		file    string
+		content string
+		wantErr bool
+	}{
+		{name: "JSON", file: "calendar.json", content: jsonCalendar},
+		{name: "YAML", file: "calendar.yaml", content: yamlCalendar},
+		{name: "Invalid Timezone", file: "calendar.json", content: strings.Replace(jsonCalendar, `"UTC"`, `"Mars/Olympus"`, 1), wantErr: true},
+		{name: "Unsupported Format", file: "calendar.toml", content: jsonCalendar, wantErr: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tc.file)
+			if err := os.WriteFile(path, []byte(tc.content), 0o600); err != nil {
+				t.Fatalf("WriteFile() error = %v", err)
+			}
+
+			calendar, err := bartender.LoadCalendar(path)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("LoadCalendar() error = %v, wantErr %v", err, tc.wantErr)
+			}
+
+			if tc.wantErr {
+				return
+			}
+
+			if !calendar.IsOpen(time.Date(2025, 1, 4, 12, 0, 0, 0, time.UTC)) {
+				t.Error("IsOpen() = false on a Saturday, want true")
+			}
+
+			if calendar.IsOpen(time.Date(2025, 12, 25, 12, 0, 0, 0, time.UTC)) {
+				t.Error("IsOpen() = true on a holiday, want false")
+			}
+		})
+	}
+}
+
+func TestInSession(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+
+	p, err := bartender.New(bartender.WithTickThreshold(10))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	trades := []bartender.Trade{
+		{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 6, 9, 0, 0, 0, newYork)},
+		{Price: decimal.NewFromInt(101), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 6, 10, 0, 0, 0, newYork)},
+		{Price: decimal.NewFromInt(102), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 6, 16, 30, 0, 0, newYork)},
+	}
+
+	bars, err := bartender.Generate(trades, p, bartender.InSession(bartender.USEquities(), bartender.SessionRegular))
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if len(bars) != 1 || bars[0].Ticks != 1 || !bars[0].Open.Equal(decimal.NewFromInt(101)) {
+		t.Errorf("Generate() = %+v, want a single bar with the regular session trade", bars)
+	}
+}
@@ -0,0 +1,273 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	decimal "github.com/alpacahq/alpacadecimal"
+)
+
+// Checkpoint holds the state of a processor, including the bar in progress, as of the last trade it processed. A
+// processor configured WithCheckpoint saves its state after every trade and, when the checkpoint already holds
+// state, resumes from it instead of starting afresh.
+//
+// To survive a restart, persist the checkpoint with encoding/json or MarshalBinary, restore it into a new Checkpoint
+// in the restarted process and replay the trades after the first Trades trades, or after LastTrade. The processor
+// then produces the same bars as one that was never interrupted.
+//
+// A processor saves its state once the bars a trade completes have been received from its output, so a checkpoint
+// taken after receiving a bar covers every trade in that bar. A checkpoint must only be used by one processor at a
+// time and can only be restored into the type of processor that saved it.
+type Checkpoint struct {
+	mu sync.Mutex
+
+	processor string
+	trades    int64
+	lastTrade time.Time
+	sequences map[string]int64
+	state     json.RawMessage
+}
+
+// checkpointData is the serialized form of a Checkpoint.
+type checkpointData struct {
+	Processor string           `json:"processor"`
+	Trades    int64            `json:"trades"`
+	LastTrade time.Time        `json:"last_trade"`
+	Sequences map[string]int64 `json:"sequences,omitempty"`
+	State     json.RawMessage  `json:"state,omitempty"`
+}
+
+// Processor returns the name of the processor configuration that saved the checkpoint.
+func (c *Checkpoint) Processor() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.processor
+}
+
+// Trades returns the number of trades processed up to the checkpoint, including those processed before any restore.
+func (c *Checkpoint) Trades() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.trades
+}
+
+// LastTrade returns the time of the last trade processed up to the checkpoint.
+func (c *Checkpoint) LastTrade() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lastTrade
+}
+
+func (c *Checkpoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.data())
+}
+
+func (c *Checkpoint) UnmarshalJSON(data []byte) error {
+	var d checkpointData
+	if err := json.Unmarshal(data, &d); err != nil {
+		return err
+	}
+
+	c.load(d)
+
+	return nil
+}
+
+func (c *Checkpoint) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(c.data()); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (c *Checkpoint) UnmarshalBinary(data []byte) error {
+	var d checkpointData
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&d); err != nil {
+		return err
+	}
+
+	if len(d.State) > 0 && !json.Valid(d.State) {
+		return fmt.Errorf("checkpoint state is not valid JSON")
+	}
+
+	c.load(d)
+
+	return nil
+}
+
+func (c *Checkpoint) data() checkpointData {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return checkpointData{
+		Processor: c.processor,
+		Trades:    c.trades,
+		LastTrade: c.lastTrade,
+		Sequences: maps.Clone(c.sequences),
+		State:     c.state,
+	}
+}
+
+func (c *Checkpoint) load(d checkpointData) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.processor = d.Processor
+	c.trades = d.Trades
+	c.lastTrade = d.LastTrade
+	c.sequences = d.Sequences
+	c.state = d.State
+}
+
+// check returns an error if the checkpoint holds the state of a processor other than the named one.
+func (c *Checkpoint) check(processor string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.state) > 0 && c.processor != processor {
+		return fmt.Errorf("checkpoint of %s cannot be restored into %s", c.processor, processor)
+	}
+
+	return nil
+}
+
+// WithCheckpoint saves the processor's state to the checkpoint after every trade and resumes from the state already
+// in it. The processor type must be given explicitly, e.g. WithCheckpoint[DollarBarConfig](checkpoint).
+func WithCheckpoint[T any, PT configurable[T]](checkpoint *Checkpoint) Option[T] {
+	return func(c *T) {
+		PT(c).setCheckpoint(checkpoint)
+	}
+}
+
+func (c *processorConfig) setCheckpoint(checkpoint *Checkpoint) {
+	c.checkpoint = checkpoint
+}
+
+// restore starts a run of the processor, resuming the sequence numbers of its bars and decoding the state held by the
+// checkpoint into state. It reports whether there was any state.
+func (c *processorConfig) restore(state any) bool {
+	c.sequences = make(map[string]int64)
+
+	if c.checkpoint == nil {
+		return false
+	}
+
+	c.checkpoint.mu.Lock()
+	defer c.checkpoint.mu.Unlock()
+
+	maps.Copy(c.sequences, c.checkpoint.sequences)
+
+	if len(c.checkpoint.state) == 0 {
+		return false
+	}
+
+	return json.Unmarshal(c.checkpoint.state, state) == nil
+}
+
+// save records state as the processor's state after the trade.
+func (c processorConfig) save(trade Trade, state any) {
+	c.store(state, &trade)
+}
+
+// saveState records state as the processor's state when it changes without a trade, such as when the clock closes a
+// bar.
+func (c processorConfig) saveState(state any) {
+	c.store(state, nil)
+}
+
+func (c processorConfig) store(state any, trade *Trade) {
+	if c.checkpoint == nil {
+		return
+	}
+
+	encoded, err := json.Marshal(state)
+	if err != nil {
+		return
+	}
+
+	c.checkpoint.mu.Lock()
+	defer c.checkpoint.mu.Unlock()
+
+	c.checkpoint.processor = c.name
+	c.checkpoint.sequences = maps.Clone(c.sequences)
+	c.checkpoint.state = encoded
+
+	if trade != nil {
+		c.checkpoint.trades++
+		c.checkpoint.lastTrade = trade.Time
+	}
+}
+
+// skip records a trade that left the processor's state unchanged.
+func (c processorConfig) skip(trade Trade) {
+	if c.checkpoint == nil {
+		return
+	}
+
+	c.checkpoint.mu.Lock()
+	defer c.checkpoint.mu.Unlock()
+
+	c.checkpoint.processor = c.name
+	c.checkpoint.trades++
+	c.checkpoint.lastTrade = trade.Time
+}
+
+// barState is the serialized form of a bar in progress, including its unexported fields.
+type barState struct {
+	Bar
+	PrevPrice   decimal.Decimal     `json:"prev_price"`
+	FeatureSums *featureAccumulator `json:"feature_sums,omitempty"`
+}
+
+func snapshotBar(b *Bar) *barState {
+	if b == nil {
+		return nil
+	}
+
+	return &barState{Bar: *b, PrevPrice: b.prevPrice, FeatureSums: b.features}
+}
+
+func (s *barState) bar() *Bar {
+	if s == nil {
+		return nil
+	}
+
+	b := s.Bar
+	b.prevPrice = s.PrevPrice
+	b.features = s.FeatureSums
+
+	return &b
+}
+
+// accumulatorState is the state of the processors that close a bar once a value accumulated over its trades reaches
+// a threshold.
+type accumulatorState struct {
+	Current   *barState       `json:"current,omitempty"`
+	Value     decimal.Decimal `json:"value"`
+	PrevPrice decimal.Decimal `json:"prev_price"`
+}
+
+// runState is the state of the processors that close a bar once a run of upticks or downticks reaches a threshold.
+type runState struct {
+	Current   *barState       `json:"current,omitempty"`
+	Up        decimal.Decimal `json:"up"`
+	Down      decimal.Decimal `json:"down"`
+	PrevPrice decimal.Decimal `json:"prev_price"`
+}
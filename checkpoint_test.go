@@ -0,0 +1,268 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	decimal "github.com/alpacahq/alpacadecimal"
+	"github.com/csgriffis/bartender"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// checkpointTrades returns trades with rising, falling and unchanged prices across two trading days.
+func checkpointTrades() []bartender.Trade {
+	prices := []int64{100, 101, 101, 103, 102, 99, 98, 98, 100, 104, 105, 103, 101, 100, 102, 106, 107, 107, 104, 103}
+	sides := []bartender.Side{bartender.SideBuy, bartender.SideSell, ""}
+
+	trades := make([]bartender.Trade, 0, len(prices))
+	start := time.Date(2025, 1, 2, 23, 59, 50, 0, time.UTC)
+
+	for i, price := range prices {
+		trades = append(trades, bartender.Trade{
+			Symbol:   "AAPL",
+			Price:    decimal.NewFromInt(price),
+			Size:     decimal.NewFromInt(int64(i%4 + 1)),
+			Side:     sides[i%len(sides)],
+			BuyRatio: decimal.NewFromFloat(0.25),
+			Time:     start.Add(time.Duration(i) * time.Second),
+		})
+	}
+
+	return trades
+}
+
+func TestCheckpoint(t *testing.T) {
+	tt := []struct {
+		name string
+		new  func(*bartender.Checkpoint) (bartender.Processor, error)
+	}{
+		{"Time", func(cp *bartender.Checkpoint) (bartender.Processor, error) {
+			return bartender.New(bartender.WithInterval(3*time.Second), bartender.WithCheckpoint[bartender.TimeBarConfig](cp))
+		}},
+		{"Tick", func(cp *bartender.Checkpoint) (bartender.Processor, error) {
+			return bartender.New(bartender.WithTickThreshold(3), bartender.WithCheckpoint[bartender.TickBarConfig](cp))
+		}},
+		{"TickImbalance", func(cp *bartender.Checkpoint) (bartender.Processor, error) {
+			return bartender.New(bartender.WithTickImbalanceThreshold(2), bartender.WithCheckpoint[bartender.TickImbalanceBarConfig](cp))
+		}},
+		{"TickRuns", func(cp *bartender.Checkpoint) (bartender.Processor, error) {
+			return bartender.New(bartender.WithTickRunThreshold(2), bartender.WithCheckpoint[bartender.TickRunsBarConfig](cp))
+		}},
+		{"Volume", func(cp *bartender.Checkpoint) (bartender.Processor, error) {
+			return bartender.New(bartender.WithVolumeThreshold(5), bartender.WithCheckpoint[bartender.VolumeBarConfig](cp))
+		}},
+		{"VolumeImbalance", func(cp *bartender.Checkpoint) (bartender.Processor, error) {
+			return bartender.New(bartender.WithVolumeImbalanceThreshold(4), bartender.WithCheckpoint[bartender.VolumeImbalanceBarConfig](cp))
+		}},
+		{"VolumeRun", func(cp *bartender.Checkpoint) (bartender.Processor, error) {
+			return bartender.New(bartender.WithVolumeRunThreshold(3), bartender.WithCheckpoint[bartender.VolumeRunBarConfig](cp))
+		}},
+		{"Dollar", func(cp *bartender.Checkpoint) (bartender.Processor, error) {
+			return bartender.New(bartender.WithDollarThreshold(500), bartender.WithCheckpoint[bartender.DollarBarConfig](cp))
+		}},
+		{"DollarImbalance", func(cp *bartender.Checkpoint) (bartender.Processor, error) {
+			return bartender.New(bartender.WithDollarImbalanceThreshold(400), bartender.WithCheckpoint[bartender.DollarImbalanceBarConfig](cp))
+		}},
+		{"DollarRun", func(cp *bartender.Checkpoint) (bartender.Processor, error) {
+			return bartender.New(bartender.WithDollarRunThreshold(300), bartender.WithCheckpoint[bartender.DollarRunBarConfig](cp))
+		}},
+		{"Range", func(cp *bartender.Checkpoint) (bartender.Processor, error) {
+			return bartender.New(bartender.WithPriceRange(3), bartender.WithCheckpoint[bartender.RangeBarConfig](cp))
+		}},
+		{"Renko", func(cp *bartender.Checkpoint) (bartender.Processor, error) {
+			return bartender.New(bartender.WithBrickSize(2), bartender.WithCheckpoint[bartender.RenkoConfig](cp))
+		}},
+		{"AdaptiveImbalance", func(cp *bartender.Checkpoint) (bartender.Processor, error) {
+			return bartender.New(bartender.WithAdaptiveVolumeImbalance(3, 2), bartender.WithCheckpoint[bartender.AdaptiveVolumeImbalanceBarConfig](cp))
+		}},
+		{"AdaptiveRun", func(cp *bartender.Checkpoint) (bartender.Processor, error) {
+			return bartender.New(bartender.WithAdaptiveDollarRun(3, 2), bartender.WithCheckpoint[bartender.AdaptiveDollarRunBarConfig](cp))
+		}},
+		{"Features", func(cp *bartender.Checkpoint) (bartender.Processor, error) {
+			return bartender.New(bartender.WithVolumeThreshold(5), bartender.WithFeatures[bartender.VolumeBarConfig](), bartender.WithCheckpoint[bartender.VolumeBarConfig](cp))
+		}},
+	}
+
+	trades := checkpointTrades()
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := tc.new(nil)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			want, err := bartender.Generate(trades, p)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			for restart := range len(trades) + 1 {
+				checkpoint := &bartender.Checkpoint{}
+
+				p, err := tc.new(checkpoint)
+				if err != nil {
+					t.Fatalf("New() error = %v", err)
+				}
+
+				got := interrupt(t, p.(bartender.ContextProcessor), checkpoint, trades[:restart])
+
+				// persist and restore the checkpoint as a restarted process would
+				data, err := json.Marshal(checkpoint)
+				if err != nil {
+					t.Fatalf("Marshal() error = %v", err)
+				}
+
+				restored := &bartender.Checkpoint{}
+				if err := json.Unmarshal(data, restored); err != nil {
+					t.Fatalf("Unmarshal() error = %v", err)
+				}
+
+				if restored.Trades() != int64(restart) {
+					t.Fatalf("Trades() = %d, want %d", restored.Trades(), restart)
+				}
+
+				p, err = tc.new(restored)
+				if err != nil {
+					t.Fatalf("New() error = %v", err)
+				}
+
+				// replay the remaining trades, which may be none
+				input := make(chan bartender.Trade, len(trades)-restart)
+				for _, trade := range trades[restart:] {
+					input <- trade
+				}
+				close(input)
+
+				rest, err := bartender.GenerateStream(input, p)
+				if err != nil {
+					t.Fatalf("GenerateStream() error = %v", err)
+				}
+
+				for bar := range rest {
+					got = append(got, bar)
+				}
+
+				if diff := cmp.Diff(want, got, cmpopts.IgnoreUnexported(bartender.Bar{}), cmpopts.EquateEmpty()); diff != "" {
+					t.Errorf("restart after %d trades (-want +got):\n%s", restart, diff)
+				}
+			}
+		})
+	}
+}
+
+// interrupt processes the trades and then stops the processor as a crash would, discarding the bar in progress. It
+// returns the bars emitted before the processor was stopped.
+func interrupt(t *testing.T, p bartender.ContextProcessor, checkpoint *bartender.Checkpoint, trades []bartender.Trade) []bartender.Bar {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	input := make(chan bartender.Trade)
+	output := p.ProcessContext(ctx, input)
+
+	var bars []bartender.Bar
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for bar := range output {
+			bars = append(bars, *bar)
+		}
+	}()
+
+	for _, trade := range trades {
+		input <- trade
+	}
+
+	// wait for the last trade to be checkpointed
+	deadline := time.Now().Add(time.Second)
+	for checkpoint.Trades() < int64(len(trades)) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Trades() = %d, want %d", checkpoint.Trades(), len(trades))
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	<-done
+
+	return bars
+}
+
+func TestCheckpoint_MarshalBinary(t *testing.T) {
+	checkpoint := &bartender.Checkpoint{}
+
+	p, err := bartender.New(bartender.WithTickThreshold(10), bartender.WithCheckpoint[bartender.TickBarConfig](checkpoint))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	trades := checkpointTrades()[:3]
+	interrupt(t, p.(bartender.ContextProcessor), checkpoint, trades)
+
+	data, err := checkpoint.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error = %v", err)
+	}
+
+	restored := &bartender.Checkpoint{}
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary() error = %v", err)
+	}
+
+	if got, want := restored.Processor(), "TickBarConfig"; got != want {
+		t.Errorf("Processor() = %q, want %q", got, want)
+	}
+
+	if got, want := restored.Trades(), int64(3); got != want {
+		t.Errorf("Trades() = %d, want %d", got, want)
+	}
+
+	if got, want := restored.LastTrade(), trades[2].Time; !got.Equal(want) {
+		t.Errorf("LastTrade() = %v, want %v", got, want)
+	}
+
+	// the restored processor completes the bar started before the restart
+	p, err = bartender.New(bartender.WithTickThreshold(10), bartender.WithCheckpoint[bartender.TickBarConfig](restored))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	bars, err := bartender.Generate(checkpointTrades()[3:4], p)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if len(bars) != 1 || bars[0].Ticks != 4 {
+		t.Errorf("Generate() = %+v, want a single bar of 4 ticks", bars)
+	}
+}
+
+func TestWithCheckpoint_Mismatch(t *testing.T) {
+	checkpoint := &bartender.Checkpoint{}
+
+	p, err := bartender.New(bartender.WithTickThreshold(10), bartender.WithCheckpoint[bartender.TickBarConfig](checkpoint))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	interrupt(t, p.(bartender.ContextProcessor), checkpoint, checkpointTrades()[:1])
+
+	if _, err := bartender.New(bartender.WithVolumeThreshold(10), bartender.WithCheckpoint[bartender.VolumeBarConfig](checkpoint)); err == nil {
+		t.Errorf("New() error = nil, want error for a checkpoint of another processor")
+	}
+}
@@ -0,0 +1,275 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender
+
+import (
+	"context"
+	"math"
+	"time"
+
+	decimal "github.com/alpacahq/alpacadecimal"
+)
+
+// Classifier fills in the aggressor side of trades that arrive without one. Trades that already carry a Side are
+// passed through unchanged, but still inform the classification of later trades.
+//
+// Classifiers expect the trades of a single symbol, in time order.
+type Classifier interface {
+	// Classify returns a channel of classified trades. It must close the returned channel once the trades channel
+	// has been closed or the context is cancelled.
+	Classify(context.Context, <-chan Trade) <-chan Trade
+}
+
+// Classified returns a Processor that classifies trades with the classifier before passing them to the processor.
+func Classified(classifier Classifier, processor Processor) Processor {
+	return classifiedProcessor{classifier: classifier, processor: processor}
+}
+
+type classifiedProcessor struct {
+	classifier Classifier
+	processor  Processor
+}
+
+func (c classifiedProcessor) Process(trades <-chan Trade) chan *Bar {
+	return c.ProcessContext(context.Background(), trades)
+}
+
+func (c classifiedProcessor) ProcessContext(ctx context.Context, trades <-chan Trade) chan *Bar {
+	return process(ctx, c.processor, c.classifier.Classify(ctx, trades))
+}
+
+// TickRule classifies a trade as a buy when its price is above the previous trade price and as a sell when below.
+// Trades at an unchanged price take the side of the previous trade. The first trade is left unclassified.
+func TickRule() Classifier {
+	return tickRuleClassifier{}
+}
+
+type tickRuleClassifier struct{}
+
+func (tickRuleClassifier) Classify(ctx context.Context, trades <-chan Trade) <-chan Trade {
+	output := make(chan Trade)
+
+	go func() {
+		defer close(output)
+
+		var prevPrice decimal.Decimal
+		var side Side
+
+		for trade := range receive(ctx, trades) {
+			if !prevPrice.IsZero() {
+				side = compareSide(trade.Price, prevPrice, side)
+			}
+
+			prevPrice = trade.Price
+
+			if trade.Side == "" {
+				trade.Side = side
+			}
+
+			if !send(ctx, output, trade) {
+				return
+			}
+		}
+	}()
+
+	return output
+}
+
+// ReverseTickRule classifies a trade as a buy when the next trade at a different price is lower and as a sell when it
+// is higher. Trades are held back until a price change is seen, so trades still waiting for one when the trade
+// channel closes are emitted unclassified.
+func ReverseTickRule() Classifier {
+	return reverseTickRuleClassifier{}
+}
+
+type reverseTickRuleClassifier struct{}
+
+func (reverseTickRuleClassifier) Classify(ctx context.Context, trades <-chan Trade) <-chan Trade {
+	output := make(chan Trade)
+
+	go func() {
+		defer close(output)
+
+		// trades at the same price waiting for the next price change
+		var pending []Trade
+
+		flush := func(side Side) bool {
+			for _, trade := range pending {
+				if trade.Side == "" {
+					trade.Side = side
+				}
+
+				if !send(ctx, output, trade) {
+					return false
+				}
+			}
+
+			pending = pending[:0]
+
+			return true
+		}
+
+		for trade := range receive(ctx, trades) {
+			if len(pending) > 0 && !trade.Price.Equal(pending[0].Price) {
+				side := SideSell
+				if pending[0].Price.GreaterThan(trade.Price) {
+					side = SideBuy
+				}
+
+				if !flush(side) {
+					return
+				}
+			}
+
+			pending = append(pending, trade)
+		}
+
+		if ctx.Err() == nil {
+			flush("")
+		}
+	}()
+
+	return output
+}
+
+// LeeReady classifies trades against the prevailing quote from the quotes channel using the Lee-Ready algorithm:
+// trades above the quote midpoint are buys, trades below it are sells and trades at the midpoint fall back to the
+// tick rule. The prevailing quote is the latest quote at or before the trade time, so quotes must be in time order.
+func LeeReady(quotes <-chan Quote) Classifier {
+	return leeReadyClassifier{quotes: quotes}
+}
+
+type leeReadyClassifier struct {
+	quotes <-chan Quote
+}
+
+func (c leeReadyClassifier) Classify(ctx context.Context, trades <-chan Trade) <-chan Trade {
+	output := make(chan Trade)
+
+	go func() {
+		defer close(output)
+
+		var prevailing, next *Quote
+		var prevPrice decimal.Decimal
+		var tickSide Side
+
+		quotes := c.quotes
+
+		// advance moves the prevailing quote forward to the latest quote at or before t
+		advance := func(t time.Time) {
+			for quotes != nil {
+				if next == nil {
+					select {
+					case <-ctx.Done():
+						return
+					case q, ok := <-quotes:
+						if !ok {
+							quotes = nil
+							return
+						}
+
+						next = &q
+					}
+				}
+
+				if next.Time.After(t) {
+					return
+				}
+
+				prevailing, next = next, nil
+			}
+		}
+
+		for trade := range receive(ctx, trades) {
+			advance(trade.Time)
+
+			if !prevPrice.IsZero() {
+				tickSide = compareSide(trade.Price, prevPrice, tickSide)
+			}
+
+			prevPrice = trade.Price
+
+			if trade.Side == "" {
+				trade.Side = tickSide
+
+				if prevailing != nil {
+					trade.Side = compareSide(trade.Price, prevailing.Mid(), tickSide)
+				}
+			}
+
+			if !send(ctx, output, trade) {
+				return
+			}
+		}
+	}()
+
+	return output
+}
+
+// BulkVolume attributes a share of each trade's size to buyers using bulk volume classification: the buy ratio is
+// Φ(ΔP/σ), where ΔP is the price change from the previous trade, σ the exponentially weighted standard deviation of
+// price changes over the window and Φ the standard normal CDF. Classified trades keep an empty Side and carry the
+// estimate in BuyRatio.
+func BulkVolume(window int) Classifier {
+	return bulkVolumeClassifier{window: window}
+}
+
+type bulkVolumeClassifier struct {
+	window int
+}
+
+func (c bulkVolumeClassifier) Classify(ctx context.Context, trades <-chan Trade) <-chan Trade {
+	output := make(chan Trade)
+
+	go func() {
+		defer close(output)
+
+		var prevPrice decimal.Decimal
+		variance := ewma{alpha: 2 / (float64(max(c.window, 1)) + 1)}
+
+		for trade := range receive(ctx, trades) {
+			ratio := 0.5
+
+			if !prevPrice.IsZero() {
+				change := trade.Price.Sub(prevPrice).InexactFloat64()
+
+				if sigma := math.Sqrt(variance.update(change * change)); sigma > 0 {
+					ratio = 0.5 * math.Erfc(-change/(sigma*math.Sqrt2))
+				}
+			}
+
+			prevPrice = trade.Price
+
+			if trade.Side == "" {
+				trade.BuyRatio = decimal.NewFromFloat(ratio)
+			}
+
+			if !send(ctx, output, trade) {
+				return
+			}
+		}
+	}()
+
+	return output
+}
+
+// compareSide returns the side implied by price relative to reference, or fallback when they are equal.
+func compareSide(price, reference decimal.Decimal, fallback Side) Side {
+	switch {
+	case price.GreaterThan(reference):
+		return SideBuy
+	case price.LessThan(reference):
+		return SideSell
+	}
+
+	return fallback
+}
+
+// Interface guards
+var _ Processor = (*classifiedProcessor)(nil)
+var _ ContextProcessor = (*classifiedProcessor)(nil)
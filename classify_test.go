@@ -0,0 +1,183 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	decimal "github.com/alpacahq/alpacadecimal"
+	"github.com/csgriffis/bartender"
+	"github.com/google/go-cmp/cmp"
+)
+
+// classify runs the trades through the classifier and returns the resulting sides.
+func classify(c bartender.Classifier, trades []bartender.Trade) []bartender.Side {
+	tradesChan := make(chan bartender.Trade)
+	go func() {
+		defer close(tradesChan)
+		for _, trade := range trades {
+			tradesChan <- trade
+		}
+	}()
+
+	sides := make([]bartender.Side, 0, len(trades))
+	for trade := range c.Classify(context.Background(), tradesChan) {
+		sides = append(sides, trade.Side)
+	}
+
+	return sides
+}
+
+func prices(sides []bartender.Side, values ...float64) []bartender.Trade {
+	trades := make([]bartender.Trade, 0, len(values))
+	for i, v := range values {
+		trade := bartender.Trade{Price: decimal.NewFromFloat(v), Size: decimal.NewFromInt(1), Time: time.Date(2025, 1, 1, 10, 0, i, 0, time.UTC)}
+		if sides != nil {
+			trade.Side = sides[i]
+		}
+		trades = append(trades, trade)
+	}
+
+	return trades
+}
+
+func TestTickRule(t *testing.T) {
+	tt := []struct {
+		name   string
+		trades []bartender.Trade
+		want   []bartender.Side
+	}{
+		{
+			name:   "Upticks and Downticks",
+			trades: prices(nil, 100, 101, 101, 100.5, 100.5),
+			want:   []bartender.Side{"", bartender.SideBuy, bartender.SideBuy, bartender.SideSell, bartender.SideSell},
+		},
+		{
+			name:   "Existing Side Kept",
+			trades: prices([]bartender.Side{"", bartender.SideSell, ""}, 100, 101, 102),
+			want:   []bartender.Side{"", bartender.SideSell, bartender.SideBuy},
+		},
+		{
+			name:   "No Trades",
+			trades: nil,
+			want:   []bartender.Side{},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(classify(bartender.TickRule(), tc.trades), tc.want); diff != "" {
+				t.Errorf("TickRule() mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReverseTickRule(t *testing.T) {
+	tt := []struct {
+		name   string
+		trades []bartender.Trade
+		want   []bartender.Side
+	}{
+		{
+			name:   "Classified by Next Price Change",
+			trades: prices(nil, 100, 101, 101, 100, 100),
+			want:   []bartender.Side{bartender.SideSell, bartender.SideBuy, bartender.SideBuy, "", ""},
+		},
+		{
+			name:   "Existing Side Kept",
+			trades: prices([]bartender.Side{bartender.SideBuy, "", ""}, 100, 101, 100),
+			want:   []bartender.Side{bartender.SideBuy, bartender.SideBuy, ""},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(classify(bartender.ReverseTickRule(), tc.trades), tc.want); diff != "" {
+				t.Errorf("ReverseTickRule() mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLeeReady(t *testing.T) {
+	quotes := make(chan bartender.Quote, 2)
+	quotes <- bartender.Quote{BidPrice: decimal.NewFromInt(99), AskPrice: decimal.NewFromInt(101), Time: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)}
+	quotes <- bartender.Quote{BidPrice: decimal.NewFromInt(101), AskPrice: decimal.NewFromInt(103), Time: time.Date(2025, 1, 1, 10, 0, 3, 0, time.UTC)}
+	close(quotes)
+
+	// mid is 100 until 10:00:03 and 102 afterwards
+	got := classify(bartender.LeeReady(quotes), prices(nil, 100.5, 99.5, 100, 101.5, 102, 102))
+	want := []bartender.Side{bartender.SideBuy, bartender.SideSell, bartender.SideBuy, bartender.SideSell, bartender.SideBuy, bartender.SideBuy}
+
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("LeeReady() mismatch (-got +want):\n%s", diff)
+	}
+}
+
+func TestBulkVolume(t *testing.T) {
+	tradesChan := make(chan bartender.Trade)
+	go func() {
+		defer close(tradesChan)
+		for _, trade := range prices([]bartender.Side{"", "", "", bartender.SideSell}, 100, 101, 100, 101) {
+			tradesChan <- trade
+		}
+	}()
+
+	var ratios []float64
+	for trade := range bartender.BulkVolume(10).Classify(context.Background(), tradesChan) {
+		ratios = append(ratios, trade.BuyRatio.InexactFloat64())
+	}
+
+	if len(ratios) != 4 {
+		t.Fatalf("BulkVolume() trades = %d, want 4", len(ratios))
+	}
+
+	if ratios[0] != 0.5 {
+		t.Errorf("BulkVolume() first ratio = %v, want 0.5", ratios[0])
+	}
+
+	if ratios[1] <= 0.5 || ratios[2] >= 0.5 {
+		t.Errorf("BulkVolume() ratios = %v, want buying on the uptick and selling on the downtick", ratios)
+	}
+
+	if ratios[3] != 0 {
+		t.Errorf("BulkVolume() classified ratio = %v, want trades with a side left unchanged", ratios[3])
+	}
+}
+
+func TestClassified(t *testing.T) {
+	tc := TestCase[int64]{
+		name:   "Tick Rule Ahead of Tick Bars",
+		input:  4,
+		trades: prices(nil, 100, 101, 101, 100),
+		want: []bartender.Bar{
+			{
+				Open:       decimal.NewFromInt(100),
+				High:       decimal.NewFromInt(101),
+				Low:        decimal.NewFromInt(100),
+				Close:      decimal.NewFromInt(100),
+				Volume:     decimal.NewFromInt(4),
+				Start:      time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+				BuyVolume:  decimal.NewFromInt(2),
+				SellVolume: decimal.NewFromInt(2),
+				Ticks:      4,
+				Upticks:    2,
+			},
+		},
+	}
+
+	p, err := bartender.New(bartender.WithTickThreshold(tc.input))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tc.Run(t, bartender.Classified(bartender.TickRule(), p))
+}
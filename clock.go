@@ -0,0 +1,108 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells processors the current time so they can close bars without waiting for the next trade.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// After sends the current time on the returned channel once the duration has elapsed.
+	After(time.Duration) <-chan time.Time
+}
+
+// SystemClock returns a Clock that reads the system time.
+func SystemClock() Clock {
+	return systemClock{}
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// FakeClock is a Clock that only moves when told to, for deterministic tests.
+type FakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []fakeTimer
+}
+
+type fakeTimer struct {
+	at time.Time
+	ch chan time.Time
+}
+
+// NewFakeClock returns a FakeClock set to now.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// After fires once the clock has been advanced by the duration. A duration that is not positive fires immediately.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+
+	c.timers = append(c.timers, fakeTimer{at: c.now.Add(d), ch: ch})
+
+	return ch
+}
+
+// Advance moves the clock forward by the duration, firing every timer that has become due.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.Set(c.Now().Add(d))
+}
+
+// Set moves the clock to now, firing every timer that has become due. The clock does not move backwards.
+func (c *FakeClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now.After(c.now) {
+		c.now = now
+	}
+
+	pending := c.timers[:0]
+	for _, timer := range c.timers {
+		if timer.at.After(c.now) {
+			pending = append(pending, timer)
+			continue
+		}
+
+		timer.ch <- c.now
+	}
+
+	c.timers = pending
+}
+
+// Interface guards
+var _ Clock = (*systemClock)(nil)
+var _ Clock = (*FakeClock)(nil)
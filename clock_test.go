@@ -0,0 +1,64 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/csgriffis/bartender"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	clock := bartender.NewFakeClock(start)
+
+	early := clock.After(time.Second)
+	late := clock.After(time.Minute)
+	now := clock.After(0)
+
+	select {
+	case got := <-now:
+		if !got.Equal(start) {
+			t.Errorf("After(0) = %v, want %v", got, start)
+		}
+	default:
+		t.Errorf("After(0) did not fire immediately")
+	}
+
+	clock.Advance(30 * time.Second)
+
+	select {
+	case got := <-early:
+		if want := start.Add(30 * time.Second); !got.Equal(want) {
+			t.Errorf("After(1s) = %v, want %v", got, want)
+		}
+	default:
+		t.Errorf("After(1s) did not fire after advancing 30s")
+	}
+
+	select {
+	case <-late:
+		t.Errorf("After(1m) fired after advancing 30s")
+	default:
+	}
+
+	// the clock does not move backwards
+	clock.Set(start)
+	if got, want := clock.Now(), start.Add(30*time.Second); !got.Equal(want) {
+		t.Errorf("Now() = %v, want %v", got, want)
+	}
+
+	clock.Set(start.Add(time.Minute))
+
+	select {
+	case <-late:
+	default:
+		t.Errorf("After(1m) did not fire after setting the clock a minute ahead")
+	}
+}
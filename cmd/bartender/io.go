@@ -0,0 +1,265 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/csgriffis/bartender"
+)
+
+// File formats of trades and bars.
+const (
+	formatCSV     = "csv"
+	formatJSONL   = "jsonl"
+	formatParquet = "parquet"
+)
+
+// format returns the explicit format, or the format implied by the extension of path, defaulting to CSV.
+func format(explicit, path string) string {
+	if explicit != "" {
+		return strings.ToLower(explicit)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jsonl", ".ndjson":
+		return formatJSONL
+	case ".parquet":
+		return formatParquet
+	}
+
+	return formatCSV
+}
+
+// openInput opens the file to read, or stdin for "-".
+func openInput(path string, stdin io.Reader) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(stdin), nil
+	}
+
+	return os.Open(path)
+}
+
+// openOutput creates the file to write, or returns stdout for "-".
+func openOutput(path string, stdout io.Writer) (io.WriteCloser, error) {
+	if path == "-" {
+		return nopWriteCloser{stdout}, nil
+	}
+
+	return os.Create(path)
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// readTrades streams the trades of r in the format. columns maps Trade fields to CSV columns, e.g.
+// "price=px,size=qty", and timeFormat is the format of the CSV time column.
+func readTrades(ctx context.Context, r io.Reader, format, columns, timeFormat string) (chan bartender.Trade, <-chan error, error) {
+	switch format {
+	case formatCSV:
+		options := []bartender.Option[bartender.TradeReaderConfig]{bartender.WithTimeFormat(timeFormat)}
+
+		if columns != "" {
+			mapping, err := parseColumns(columns)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			options = append(options, bartender.WithTradeColumns(mapping))
+		}
+
+		return bartender.ReadTradesCSV(ctx, r, options...)
+	case formatJSONL:
+		trades, errs := readTradesJSONL(ctx, r)
+
+		return trades, errs, nil
+	case formatParquet:
+		return readTradesParquet(ctx, r)
+	}
+
+	return nil, nil, fmt.Errorf("unknown trade format %q", format)
+}
+
+// parseColumns reads a mapping of Trade fields to CSV columns, such as "price=px,size=qty". Fields that are not
+// mapped keep their default column.
+func parseColumns(mapping string) (bartender.TradeColumns, error) {
+	columns := bartender.DefaultTradeColumns()
+
+	fields := map[string]*string{
+		"symbol":    &columns.Symbol,
+		"price":     &columns.Price,
+		"size":      &columns.Size,
+		"side":      &columns.Side,
+		"time":      &columns.Time,
+		"id":        &columns.ID,
+		"action":    &columns.Action,
+		"buy_ratio": &columns.BuyRatio,
+	}
+
+	for _, pair := range strings.Split(mapping, ",") {
+		field, column, ok := strings.Cut(pair, "=")
+		if !ok {
+			return columns, fmt.Errorf("invalid column mapping %q, want field=column", pair)
+		}
+
+		target, ok := fields[strings.TrimSpace(field)]
+		if !ok {
+			return columns, fmt.Errorf("unknown trade field %q", field)
+		}
+
+		*target = strings.TrimSpace(column)
+	}
+
+	return columns, nil
+}
+
+// readTradesJSONL streams trades encoded as JSON, one per line, until r ends or the context is cancelled. A line that
+// cannot be decoded stops the stream with an error.
+func readTradesJSONL(ctx context.Context, r io.Reader) (chan bartender.Trade, <-chan error) {
+	trades := make(chan bartender.Trade)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(trades)
+
+		decoder := json.NewDecoder(r)
+
+		for line := 1; ; line++ {
+			var trade bartender.Trade
+			if err := decoder.Decode(&trade); err != nil {
+				if !errors.Is(err, io.EOF) {
+					errs <- fmt.Errorf("trade %d: %w", line, err)
+				}
+
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			case trades <- trade:
+			}
+		}
+	}()
+
+	return trades, errs
+}
+
+// readTradesParquet reads the trades of a Parquet file, then streams them. Parquet files cannot be read from stdin.
+func readTradesParquet(ctx context.Context, r io.Reader) (chan bartender.Trade, <-chan error, error) {
+	file, ok := r.(*os.File)
+	if !ok || file == os.Stdin {
+		return nil, nil, errors.New("parquet trades must be read from a file")
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	source, err := bartender.ReadTradesParquet(file, info.Size())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	trades := make(chan bartender.Trade)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(trades)
+
+		for _, trade := range source {
+			select {
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			case trades <- trade:
+			}
+		}
+	}()
+
+	return trades, errs, nil
+}
+
+// barWriter writes bars in an output format.
+type barWriter interface {
+	Write(bartender.Bar) error
+	Flush() error
+}
+
+// newBarWriter creates a writer of bars in the format to w, rounding prices to precision decimal places unless it is
+// negative.
+func newBarWriter(w io.Writer, format string, precision int) (barWriter, error) {
+	var places bartender.Precision
+	if precision >= 0 {
+		places = bartender.Precision{"": int32(precision)}
+	}
+
+	switch format {
+	case formatCSV:
+		return bartender.NewBarWriter(w, bartender.WithSymbolPrecision(places))
+	case formatJSONL:
+		buffered := bufio.NewWriter(w)
+
+		return jsonlWriter{buffered: buffered, encoder: json.NewEncoder(buffered), precision: places}, nil
+	case formatParquet:
+		var options []bartender.Option[bartender.ParquetWriterConfig]
+		if precision >= 0 {
+			options = append(options, bartender.WithDecimalScale(int32(precision)))
+		}
+
+		writer, err := bartender.NewParquetBarWriter(w, options...)
+		if err != nil {
+			return nil, err
+		}
+
+		return parquetWriter{writer}, nil
+	}
+
+	return nil, fmt.Errorf("unknown bar format %q", format)
+}
+
+// jsonlWriter writes bars encoded as JSON, one per line.
+type jsonlWriter struct {
+	buffered  *bufio.Writer
+	encoder   *json.Encoder
+	precision bartender.Precision
+}
+
+func (w jsonlWriter) Write(bar bartender.Bar) error {
+	return w.encoder.Encode(w.precision.Round(bar))
+}
+
+func (w jsonlWriter) Flush() error {
+	return w.buffered.Flush()
+}
+
+// parquetWriter writes bars to a Parquet file, completing it on Flush.
+type parquetWriter struct {
+	*bartender.ParquetBarWriter
+}
+
+func (w parquetWriter) Flush() error {
+	return w.Close()
+}
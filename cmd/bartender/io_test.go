@@ -0,0 +1,77 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/csgriffis/bartender"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFormat(t *testing.T) {
+	tt := []struct {
+		explicit, path, want string
+	}{
+		{"", "-", formatCSV},
+		{"", "trades.csv", formatCSV},
+		{"", "trades.JSONL", formatJSONL},
+		{"", "trades.ndjson", formatJSONL},
+		{"", "bars.parquet", formatParquet},
+		{"JSONL", "trades.csv", formatJSONL},
+	}
+
+	for _, tc := range tt {
+		if got := format(tc.explicit, tc.path); got != tc.want {
+			t.Errorf("format(%q, %q) = %q, want %q", tc.explicit, tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestParseColumns(t *testing.T) {
+	got, err := parseColumns("price=px, size = qty,time=ts")
+	if err != nil {
+		t.Fatalf("parseColumns() error = %v", err)
+	}
+
+	want := bartender.DefaultTradeColumns()
+	want.Price, want.Size, want.Time = "px", "qty", "ts"
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("parseColumns() (-want +got):\n%s", diff)
+	}
+
+	for _, invalid := range []string{"price", "bid=px"} {
+		if _, err := parseColumns(invalid); err == nil {
+			t.Errorf("parseColumns(%q) error = nil, want error", invalid)
+		}
+	}
+}
+
+func TestReadTradesJSONL(t *testing.T) {
+	input := `{"symbol":"AAPL","price":"100","size":"1","time":"2025-01-02T15:00:00Z"}
+{"symbol":"AAPL","price":
+`
+
+	trades, errs := readTradesJSONL(context.Background(), strings.NewReader(input))
+
+	var count int
+	for range trades {
+		count++
+	}
+
+	if count != 1 {
+		t.Errorf("readTradesJSONL() trades = %d, want 1", count)
+	}
+
+	if err := <-errs; err == nil {
+		t.Error("readTradesJSONL() stream error = nil, want error for the truncated trade")
+	}
+}
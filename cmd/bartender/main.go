@@ -0,0 +1,234 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+// Command bartender builds bars from a file of trades.
+//
+// Trades are read from CSV, JSON Lines or Parquet files, or from stdin, and the bars are written as CSV, JSON Lines or
+// Parquet to a file or stdout. The type of bar is given as a processor spec, see bartender.ParseProcessorSpec, or as a
+// bare type with the -threshold, -interval, -warmup, -span and -reversal flags filling in its parameters. For example,
+// to build dollar imbalance bars from a CSV file:
+//
+//	bartender -type dollar-imbalance:threshold=1e6 -in trades.csv -out bars.csv
+//	bartender -type dollar-imbalance -threshold 1e6 -in trades.csv -out bars.csv
+//
+// Run bartender -h for the full list of flags.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/csgriffis/bartender"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintf(os.Stderr, "bartender: %v\n", err)
+		}
+
+		os.Exit(1)
+	}
+}
+
+// options are the command-line flags.
+type options struct {
+	processor processorOptions
+
+	in, inFormat   string
+	columns        string
+	timeFormat     string
+	out, outFormat string
+	precision      int
+
+	symbols  string
+	calendar string
+	session  string
+	from, to string
+}
+
+// run parses the arguments and builds bars from the trades, as main does.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	var opts options
+
+	flags := flag.NewFlagSet("bartender", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: bartender -type TYPE[:PARAM=VALUE,...] [flags]\n\nTypes: %s\n\nFlags:\n",
+			strings.Join(bartender.ProcessorTypes(), ", "))
+		flags.PrintDefaults()
+	}
+
+	flags.StringVar(&opts.processor.spec, "type", "", "type of bar to build and its parameters, e.g. dollar:threshold=5e6 or a JSON object (required)")
+	flags.Float64("threshold", 0, "threshold of tick, volume and dollar bars, price range of range bars or brick size of renko bricks")
+	flags.Duration("interval", 0, "interval of time bars")
+	flags.Int64("warmup", 0, "expected ticks per bar until the first adaptive bar closes")
+	flags.Int64("span", 0, "span of the moving averages of adaptive bars")
+	flags.Int64("reversal", 0, "bricks needed for a renko reversal (default 2)")
+	flags.BoolVar(&opts.processor.partition, "partition", false, "build bars for each symbol separately")
+
+	flags.StringVar(&opts.in, "in", "-", "trade file to read, or - for stdin")
+	flags.StringVar(&opts.inFormat, "in-format", "", "format of the trades, csv, jsonl or parquet (default from the file extension, or csv)")
+	flags.StringVar(&opts.columns, "columns", "", "CSV columns of trade fields, e.g. price=px,size=qty,time=ts")
+	flags.StringVar(&opts.timeFormat, "time-format", "", "CSV time layout, or unix, unix_ms, unix_us or unix_ns (default RFC 3339)")
+	flags.StringVar(&opts.out, "out", "-", "bar file to write, or - for stdout")
+	flags.StringVar(&opts.outFormat, "out-format", "", "format of the bars, csv, jsonl or parquet (default from the file extension, or csv)")
+	flags.IntVar(&opts.precision, "precision", -1, "decimal places of bar prices, or of every decimal in parquet files (default exact, or 8 in parquet files)")
+
+	flags.StringVar(&opts.symbols, "symbols", "", "comma-separated symbols to keep (default all)")
+	flags.StringVar(&opts.calendar, "calendar", "", "keep trades in the sessions of a calendar: us-equities, cme or a JSON or YAML file")
+	flags.StringVar(&opts.session, "session", "", "kind of calendar session to keep, regular or extended (default any)")
+	flags.StringVar(&opts.from, "from", "", "keep trades at or after an RFC 3339 time")
+	flags.StringVar(&opts.to, "to", "", "keep trades before an RFC 3339 time")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(flags.Args(), " "))
+	}
+
+	// the parameter flags given fill in the spec
+	opts.processor.shorthands = make(map[string]string)
+	flags.Visit(func(f *flag.Flag) {
+		if slices.Contains(shorthandFlags, f.Name) {
+			opts.processor.shorthands[f.Name] = f.Value.String()
+		}
+	})
+
+	processor, err := newProcessor(opts.processor)
+	if err != nil {
+		return err
+	}
+
+	filters, err := opts.filters()
+	if err != nil {
+		return err
+	}
+
+	in, err := openInput(opts.in, stdin)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := openOutput(opts.out, stdout)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	// stop reading trades if the bars cannot be built or written
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	trades, tradeErrs, err := readTrades(ctx, in, format(opts.inFormat, opts.in), opts.columns, opts.timeFormat)
+	if err != nil {
+		return err
+	}
+
+	writer, err := newBarWriter(out, format(opts.outFormat, opts.out), opts.precision)
+	if err != nil {
+		return err
+	}
+
+	bars, barErrs, err := bartender.GenerateStreamContext(ctx, trades, processor, filters...)
+	if err != nil {
+		return err
+	}
+
+	for bar := range bars {
+		if err := writer.Write(bar); err != nil {
+			return err
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+
+	if err := <-barErrs; err != nil {
+		return err
+	}
+
+	return <-tradeErrs
+}
+
+// filters returns the trade filters selected by the flags.
+func (o options) filters() ([]bartender.FilterFunc, error) {
+	var filters []bartender.FilterFunc
+
+	if o.symbols != "" {
+		symbols := make(map[string]bool)
+		for _, symbol := range strings.Split(o.symbols, ",") {
+			symbols[strings.TrimSpace(symbol)] = true
+		}
+
+		filters = append(filters, func(t bartender.Trade) bool {
+			return symbols[t.Symbol]
+		})
+	}
+
+	if o.calendar != "" {
+		calendar, err := loadCalendar(o.calendar)
+		if err != nil {
+			return nil, err
+		}
+
+		var kinds []bartender.SessionKind
+		if o.session != "" {
+			kinds = append(kinds, bartender.SessionKind(o.session))
+		}
+
+		filters = append(filters, bartender.InSession(calendar, kinds...))
+	}
+
+	for _, bound := range []struct {
+		value  string
+		before bool
+	}{{o.from, false}, {o.to, true}} {
+		if bound.value == "" {
+			continue
+		}
+
+		t, err := time.Parse(time.RFC3339Nano, bound.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time %q: %w", bound.value, err)
+		}
+
+		before := bound.before
+		filters = append(filters, func(trade bartender.Trade) bool {
+			return trade.Time.Before(t) == before
+		})
+	}
+
+	return filters, nil
+}
+
+// loadCalendar returns a built-in calendar by name, or loads one from a file.
+func loadCalendar(name string) (*bartender.Calendar, error) {
+	switch name {
+	case "us-equities":
+		return bartender.USEquities(), nil
+	case "cme":
+		return bartender.CME(), nil
+	}
+
+	return bartender.LoadCalendar(name)
+}
@@ -0,0 +1,166 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	decimal "github.com/alpacahq/alpacadecimal"
+	"github.com/csgriffis/bartender"
+)
+
+const tradesCSV = `symbol,time,price,size,side
+AAPL,2025-01-02T15:00:00Z,100,10,buy
+MSFT,2025-01-02T15:00:00Z,400,1,buy
+AAPL,2025-01-02T15:00:01Z,101.125,10,sell
+AAPL,2025-01-02T15:00:02Z,102,5,buy
+`
+
+func TestRun(t *testing.T) {
+	tt := []struct {
+		name    string
+		args    []string
+		want    string // output, or a part of it
+		wantErr bool
+	}{
+		{
+			name: "Dollar Bars",
+			args: []string{"-type", "dollar", "-threshold", "2000", "-symbols", "AAPL"},
+			want: "symbol,start,open,high,low,close,volume,ticks,upticks,buy_volume,sell_volume,vwap,notional,end,last_trade_time,downticks,threshold,provisional,revision,processor,sequence,close_reason\n" +
+				"AAPL,2025-01-02T15:00:00Z,100,101.125,100,101.125,20,2,1,10,10,100.5625,2011.25,2025-01-02T15:00:01Z,2025-01-02T15:00:01Z,0,0,false,0,DollarBarConfig,1,threshold\n" +
+				"AAPL,2025-01-02T15:00:02Z,102,102,102,102,5,1,0,5,0,102,510,2025-01-02T15:00:02Z,2025-01-02T15:00:02Z,0,0,false,0,DollarBarConfig,2,end_of_stream\n",
+		},
+		{
+			name: "Partitioned JSON Lines with Precision",
+			args: []string{"-type", "tick", "-threshold", "5", "-partition", "-out-format", "jsonl", "-precision", "2", "-to", "2025-01-02T15:00:02Z"},
+			want: `{"symbol":"AAPL","open":"100","high":"101.13","low":"100","close":"101.13"`,
+		},
+		{
+			name: "Spec",
+			args: []string{"--type", "dollar-imbalance:threshold=1e6"},
+			want: "symbol,start,open,high,low,close",
+		},
+		{
+			name: "Bare Type with Shorthand",
+			args: []string{"--type", "dollar-imbalance", "--threshold", "1e6"},
+			want: "symbol,start,open,high,low,close",
+		},
+		{
+			name: "No Bars",
+			args: []string{"-type", "dollar", "-threshold", "2000", "-symbols", "TSLA"},
+			want: "symbol,start,open,high,low,close,volume,ticks,upticks,buy_volume,sell_volume,vwap,notional,end,last_trade_time,downticks,threshold,provisional,revision,processor,sequence,close_reason\n",
+		},
+		{name: "Shorthand Without Parameter", args: []string{"-type", "dollar", "-threshold", "1", "-interval", "1m"}, wantErr: true},
+		{name: "Missing Type", args: []string{"-threshold", "1"}, wantErr: true},
+		{name: "Unknown Format", args: []string{"-type", "tick", "-threshold", "1", "-in-format", "xml"}, wantErr: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			err := run(context.Background(), tc.args, strings.NewReader(tradesCSV), &stdout, &stderr)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("run() error = %v, wantErr %v", err, tc.wantErr)
+			}
+
+			if !strings.Contains(stdout.String(), tc.want) {
+				t.Errorf("run() output = %q, want it to contain %q", stdout.String(), tc.want)
+			}
+		})
+	}
+}
+
+func TestRun_Files(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "trades.jsonl")
+	out := filepath.Join(dir, "bars.csv")
+
+	trades := `{"symbol":"AAPL","price":"100","size":"1","side":"buy","time":"2025-01-02T15:00:00Z"}
+{"symbol":"AAPL","price":"101","size":"1","side":"buy","time":"2025-01-02T15:01:30Z"}
+`
+	if err := os.WriteFile(in, []byte(trades), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if err := run(context.Background(), []string{"-type", "time", "-interval", "1m", "-in", in, "-out", out}, nil, nil, nil); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+
+	// a header and a bar for each minute
+	if lines := strings.Count(string(data), "\n"); lines != 3 {
+		t.Errorf("run() wrote %d lines, want 3:\n%s", lines, data)
+	}
+}
+
+func TestRun_Parquet(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "trades.parquet")
+	out := filepath.Join(dir, "bars.parquet")
+
+	file, err := os.Create(in)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	w, err := bartender.NewParquetTradeWriter(file)
+	if err != nil {
+		t.Fatalf("NewParquetTradeWriter() error = %v", err)
+	}
+
+	start := time.Date(2025, 1, 2, 15, 0, 0, 0, time.UTC)
+	for i := range 6 {
+		trade := bartender.Trade{Symbol: "AAPL", Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Time: start.Add(time.Duration(i) * time.Second)}
+		if err := w.Write(trade); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if err := file.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if err := run(context.Background(), []string{"-type", "tick:threshold=3", "-in", in, "-out", out}, nil, nil, nil); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+
+	bars, err := bartender.ReadBarsParquet(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("ReadBarsParquet() error = %v", err)
+	}
+
+	if len(bars) != 2 || bars[0].Ticks != 3 {
+		t.Errorf("run() wrote %+v, want 2 bars of 3 ticks", bars)
+	}
+
+	// parquet trades are not read from stdin
+	if err := run(context.Background(), []string{"-type", "tick:threshold=3", "-in-format", "parquet"}, strings.NewReader(""), io.Discard, io.Discard); err == nil {
+		t.Error("run() error = nil, want error for parquet trades on stdin")
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package main
+
+import (
+	"errors"
+
+	"github.com/csgriffis/bartender"
+)
+
+// processorOptions are the flags selecting and configuring the processor.
+type processorOptions struct {
+	spec string
+	// shorthands are the values of the parameter flags given, such as -threshold, by flag name
+	shorthands map[string]string
+	partition  bool
+}
+
+// shorthandFlags are the flags that fill in a parameter of the spec.
+var shorthandFlags = []string{"threshold", "interval", "warmup", "span", "reversal"}
+
+// newProcessor creates the processor described by a spec such as "dollar:threshold=5e6", or a bare type with its
+// parameters given by the shorthand flags, with a processor per symbol if partitioned.
+func newProcessor(o processorOptions) (bartender.Processor, error) {
+	if o.spec == "" {
+		return nil, errors.New("-type is required")
+	}
+
+	parsed, err := bartender.ParseProcessorSpec(o.spec)
+	if err != nil {
+		return nil, err
+	}
+
+	// parameters in the spec take precedence over the flags
+	for flag, value := range o.shorthands {
+		param := shorthandParam(parsed.Type, flag)
+		if _, ok := parsed.Params[param]; !ok {
+			parsed.Params[param] = value
+		}
+	}
+
+	// check the spec before any trades are read
+	processor, err := bartender.NewFromSpec(parsed)
+	if err != nil {
+		return nil, err
+	}
+
+	if o.partition {
+		return bartender.Partition(func(string) (bartender.Processor, error) {
+			return bartender.NewFromSpec(parsed)
+		}), nil
+	}
+
+	return processor, nil
+}
+
+// shorthandParam returns the spec parameter of the processor type that a shorthand flag fills in. The threshold is
+// the price range of range bars and the brick size of renko bricks.
+func shorthandParam(kind, flag string) string {
+	switch {
+	case flag == "threshold" && kind == "range":
+		return "range"
+	case flag == "threshold" && kind == "renko":
+		return "size"
+	}
+
+	return flag
+}
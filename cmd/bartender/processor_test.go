@@ -0,0 +1,43 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package main
+
+import (
+	"testing"
+)
+
+func TestNewProcessor(t *testing.T) {
+	tt := []struct {
+		name       string
+		spec       string
+		shorthands map[string]string
+		wantErr    bool
+	}{
+		{name: "Text", spec: "dollar-imbalance:threshold=1e6"},
+		{name: "JSON", spec: `{"type": "time", "interval": "1m"}`},
+		{name: "Missing", wantErr: true},
+		{name: "Unknown Type", spec: "heikin-ashi", wantErr: true},
+		{name: "Invalid Parameter", spec: "renko:size=0", wantErr: true},
+		{name: "Shorthand", spec: "dollar-imbalance", shorthands: map[string]string{"threshold": "1e+06"}},
+		{name: "Shorthand Brick Size", spec: "renko", shorthands: map[string]string{"threshold": "0.5", "reversal": "3"}},
+		{name: "Shorthand Adaptive", spec: "adaptive-tick-run", shorthands: map[string]string{"warmup": "10", "span": "5"}},
+		{name: "Spec Over Shorthand", spec: "time:interval=1m", shorthands: map[string]string{"interval": "0s"}},
+		{name: "Unknown Shorthand", spec: "dollar", shorthands: map[string]string{"threshold": "1", "interval": "1m0s"}, wantErr: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, partition := range []bool{false, true} {
+				o := processorOptions{spec: tc.spec, shorthands: tc.shorthands, partition: partition}
+				if _, err := newProcessor(o); (err != nil) != tc.wantErr {
+					t.Errorf("newProcessor(partition %v) error = %v, wantErr %v", partition, err, tc.wantErr)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,357 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"strconv"
+	"strings"
+	"time"
+
+	decimal "github.com/alpacahq/alpacadecimal"
+)
+
+// Time formats for integer timestamps, accepted by WithTimeFormat alongside time.Parse layouts.
+const (
+	TimeFormatUnix      = "unix"
+	TimeFormatUnixMilli = "unix_ms"
+	TimeFormatUnixMicro = "unix_us"
+	TimeFormatUnixNano  = "unix_ns"
+)
+
+// TradeColumns names the header columns of a trade CSV file holding each field of a Trade. The Price, Size and Time
+// columns must be present; the others are read when the file has them.
+type TradeColumns struct {
+	Symbol   string
+	Price    string
+	Size     string
+	Side     string
+	Time     string
+	ID       string
+	Action   string
+	BuyRatio string
+}
+
+// DefaultTradeColumns names the columns after the JSON fields of Trade.
+func DefaultTradeColumns() TradeColumns {
+	return TradeColumns{
+		Symbol:   "symbol",
+		Price:    "price",
+		Size:     "size",
+		Side:     "side",
+		Time:     "time",
+		ID:       "id",
+		Action:   "action",
+		BuyRatio: "buy_ratio",
+	}
+}
+
+// WithTradeColumns maps the fields of a Trade to the header columns of the file, replacing DefaultTradeColumns.
+func WithTradeColumns(columns TradeColumns) Option[TradeReaderConfig] {
+	return func(c *TradeReaderConfig) {
+		c.columns = &columns
+	}
+}
+
+// WithTimeFormat parses the time column with a time.Parse layout, or as an integer timestamp with one of the
+// TimeFormatUnix formats. Times are parsed as RFC 3339 by default.
+func WithTimeFormat(format string) Option[TradeReaderConfig] {
+	return func(c *TradeReaderConfig) {
+		c.timeFormat = format
+	}
+}
+
+// WithDelimiter sets the field delimiter of the file, a comma by default.
+func WithDelimiter(delimiter rune) Option[TradeReaderConfig] {
+	return func(c *TradeReaderConfig) {
+		c.delimiter = delimiter
+	}
+}
+
+// TradeReaderConfig configures ReadTradesCSV.
+type TradeReaderConfig struct {
+	columns    *TradeColumns
+	timeFormat string
+	delimiter  rune
+}
+
+// ReadTradesCSV streams the trades of a CSV file with a header row, such as to GenerateStream, until the file ends or
+// the context is cancelled. The header is read before ReadTradesCSV returns, so a file without the required columns
+// is reported by the returned error.
+//
+// A record that cannot be read stops the stream: the trade channel is closed and the error, naming the line of the
+// record, is sent on the error channel. The error channel is closed once the trade channel has been closed.
+func ReadTradesCSV(ctx context.Context, r io.Reader, options ...Option[TradeReaderConfig]) (chan Trade, <-chan error, error) {
+	cfg, err := configure(options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	columns := DefaultTradeColumns()
+	if cfg.columns != nil {
+		columns = *cfg.columns
+	}
+
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true
+
+	if cfg.delimiter != 0 {
+		reader.Comma = cfg.delimiter
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read header: %w", err)
+	}
+
+	index, err := indexColumns(header, columns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	trades := make(chan Trade)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(trades)
+
+		for {
+			record, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			trade, err := cfg.parseTrade(record, index)
+			if err != nil {
+				line, _ := reader.FieldPos(0)
+				errs <- fmt.Errorf("line %d: %w", line, err)
+				return
+			}
+
+			if !send(ctx, trades, trade) {
+				errs <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	return trades, errs, nil
+}
+
+// tradeIndex holds the position of each Trade field in a record, or -1 for fields the file does not have.
+type tradeIndex struct {
+	symbol, price, size, side, time, id, action, buyRatio int
+}
+
+// indexColumns finds the columns in the header, returning an error if a required column is missing.
+func indexColumns(header []string, columns TradeColumns) (tradeIndex, error) {
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		// ignore a byte order mark at the start of the file
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+
+		positions[strings.TrimSpace(name)] = i
+	}
+
+	find := func(name string) int {
+		if i, ok := positions[name]; ok && name != "" {
+			return i
+		}
+
+		return -1
+	}
+
+	index := tradeIndex{
+		symbol:   find(columns.Symbol),
+		price:    find(columns.Price),
+		size:     find(columns.Size),
+		side:     find(columns.Side),
+		time:     find(columns.Time),
+		id:       find(columns.ID),
+		action:   find(columns.Action),
+		buyRatio: find(columns.BuyRatio),
+	}
+
+	for _, required := range []struct {
+		field, name string
+		index       int
+	}{
+		{"price", columns.Price, index.price},
+		{"size", columns.Size, index.size},
+		{"time", columns.Time, index.time},
+	} {
+		if required.index < 0 {
+			return index, fmt.Errorf("header is missing the %s column %q", required.field, required.name)
+		}
+	}
+
+	return index, nil
+}
+
+// parseTrade reads a trade from the record.
+func (c TradeReaderConfig) parseTrade(record []string, index tradeIndex) (Trade, error) {
+	var trade Trade
+	var err error
+
+	field := func(i int) string {
+		if i < 0 {
+			return ""
+		}
+
+		return strings.TrimSpace(record[i])
+	}
+
+	trade.Symbol = field(index.symbol)
+	trade.ID = field(index.id)
+	trade.Action = Action(strings.ToLower(field(index.action)))
+
+	trade.Price, err = decimal.NewFromString(field(index.price))
+	if err != nil {
+		return trade, fmt.Errorf("failed to parse price: %w", err)
+	}
+
+	trade.Size, err = decimal.NewFromString(field(index.size))
+	if err != nil {
+		return trade, fmt.Errorf("failed to parse size: %w", err)
+	}
+
+	trade.Time, err = c.parseTime(field(index.time))
+	if err != nil {
+		return trade, fmt.Errorf("failed to parse time: %w", err)
+	}
+
+	switch side := Side(strings.ToLower(field(index.side))); side {
+	case SideBuy, SideSell, "":
+		trade.Side = side
+	default:
+		return trade, fmt.Errorf("unknown side %q", side)
+	}
+
+	if ratio := field(index.buyRatio); ratio != "" {
+		trade.BuyRatio, err = decimal.NewFromString(ratio)
+		if err != nil {
+			return trade, fmt.Errorf("failed to parse buy ratio: %w", err)
+		}
+	}
+
+	return trade, nil
+}
+
+// parseTime parses a timestamp in the configured format.
+func (c TradeReaderConfig) parseTime(value string) (time.Time, error) {
+	var epoch func(int64) time.Time
+
+	switch c.timeFormat {
+	case "":
+		return time.Parse(time.RFC3339Nano, value)
+	case TimeFormatUnix:
+		epoch = func(n int64) time.Time { return time.Unix(n, 0) }
+	case TimeFormatUnixMilli:
+		epoch = time.UnixMilli
+	case TimeFormatUnixMicro:
+		epoch = time.UnixMicro
+	case TimeFormatUnixNano:
+		epoch = func(n int64) time.Time { return time.Unix(0, n) }
+	default:
+		return time.Parse(c.timeFormat, value)
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return epoch(n).UTC(), nil
+}
+
+// WithPricePrecision rounds the prices written by a BarWriter to the number of decimal places, except for symbols
+// given a precision of their own by WithSymbolPrecision. Prices are written exactly by default.
+func WithPricePrecision(places int32) Option[BarWriterConfig] {
+	return WithSymbolPrecision(Precision{"": places})
+}
+
+// WithSymbolPrecision rounds the prices of each symbol written by a BarWriter to its number of decimal places.
+func WithSymbolPrecision(precision Precision) Option[BarWriterConfig] {
+	return func(c *BarWriterConfig) {
+		if c.precision == nil {
+			c.precision = make(Precision)
+		}
+
+		maps.Copy(c.precision, precision)
+	}
+}
+
+// BarWriterConfig configures a BarWriter.
+type BarWriterConfig struct {
+	precision Precision
+}
+
+// BarWriter writes bars as CSV, in the columns of Bar.MarshalCSV, after a header row naming them.
+type BarWriter struct {
+	config BarWriterConfig
+	writer *csv.Writer
+	header bool
+}
+
+// NewBarWriter creates a BarWriter that writes to w.
+func NewBarWriter(w io.Writer, options ...Option[BarWriterConfig]) (*BarWriter, error) {
+	cfg, err := configure(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BarWriter{config: cfg, writer: csv.NewWriter(w)}, nil
+}
+
+// Write writes the bar, preceded by the header row if it is the first bar. Writes are buffered until Flush.
+func (w *BarWriter) Write(bar Bar) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	return w.writer.Write(bar.record(w.config.precision))
+}
+
+// Flush writes any buffered bars to the underlying writer, preceded by the header row if no bars were written.
+func (w *BarWriter) Flush() error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	w.writer.Flush()
+
+	return w.writer.Error()
+}
+
+// writeHeader writes the header row unless it has been written.
+func (w *BarWriter) writeHeader() error {
+	if w.header {
+		return nil
+	}
+
+	if err := w.writer.Write(barColumns); err != nil {
+		return err
+	}
+
+	w.header = true
+
+	return nil
+}
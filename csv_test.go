@@ -0,0 +1,200 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	decimal "github.com/alpacahq/alpacadecimal"
+	"github.com/csgriffis/bartender"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestReadTradesCSV(t *testing.T) {
+	tt := []struct {
+		name    string
+		input   string
+		options []bartender.Option[bartender.TradeReaderConfig]
+		want    []bartender.Trade
+		wantErr bool
+	}{
+		{
+			name:  "Default Columns",
+			input: "symbol,time,price,size,side\nAAPL,2025-01-01T10:00:00Z,100.5,10,buy\nAAPL,2025-01-01T10:00:01.5Z,100.25,5,SELL\n",
+			want: []bartender.Trade{
+				{Symbol: "AAPL", Price: decimal.RequireFromString("100.5"), Size: decimal.NewFromInt(10), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)},
+				{Symbol: "AAPL", Price: decimal.RequireFromString("100.25"), Size: decimal.NewFromInt(5), Side: bartender.SideSell, Time: time.Date(2025, 1, 1, 10, 0, 1, 5e8, time.UTC)},
+			},
+		},
+		{
+			name:  "Column Mapping and Epoch Nanoseconds",
+			input: "ts_ns;px;qty;ticker\n1735725600000000001;100;1;MSFT\n",
+			options: []bartender.Option[bartender.TradeReaderConfig]{
+				bartender.WithTradeColumns(bartender.TradeColumns{Symbol: "ticker", Price: "px", Size: "qty", Time: "ts_ns"}),
+				bartender.WithTimeFormat(bartender.TimeFormatUnixNano),
+				bartender.WithDelimiter(';'),
+			},
+			want: []bartender.Trade{
+				{Symbol: "MSFT", Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Time: time.Date(2025, 1, 1, 10, 0, 0, 1, time.UTC)},
+			},
+		},
+		{
+			name:    "Layout",
+			input:   "time,price,size\n2025-01-01 10:00:00,100,1\n",
+			options: []bartender.Option[bartender.TradeReaderConfig]{bartender.WithTimeFormat(time.DateTime)},
+			want: []bartender.Trade{
+				{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Time: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name:    "Invalid Record",
+			input:   "time,price,size\n2025-01-01T10:00:00Z,100,1\n2025-01-01T10:00:01Z,abc,1\n",
+			want:    []bartender.Trade{{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Time: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)}},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			trades, errs, err := bartender.ReadTradesCSV(context.Background(), strings.NewReader(tc.input), tc.options...)
+			if err != nil {
+				t.Fatalf("ReadTradesCSV() error = %v", err)
+			}
+
+			var got []bartender.Trade
+			for trade := range trades {
+				got = append(got, trade)
+			}
+
+			if err := <-errs; (err != nil) != tc.wantErr {
+				t.Errorf("ReadTradesCSV() stream error = %v, wantErr %v", err, tc.wantErr)
+			}
+
+			if diff := cmp.Diff(tc.want, got, cmpopts.EquateComparable(decimal.Decimal{})); diff != "" {
+				t.Errorf("ReadTradesCSV() (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReadTradesCSV_MissingColumn(t *testing.T) {
+	_, _, err := bartender.ReadTradesCSV(context.Background(), strings.NewReader("time,size\n2025-01-01T10:00:00Z,1\n"))
+	if err == nil {
+		t.Error("ReadTradesCSV() error = nil, want error for a missing price column")
+	}
+}
+
+func TestBarWriter(t *testing.T) {
+	bar := bartender.Bar{
+		Symbol: "AAPL",
+		Open:   decimal.RequireFromString("100.125"),
+		High:   decimal.RequireFromString("101.5"),
+		Low:    decimal.RequireFromString("99.875"),
+		Close:  decimal.RequireFromString("100.5"),
+		Volume: decimal.NewFromInt(10),
+		Start:  time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+		VWAP:   decimal.RequireFromString("100.3125"),
+	}
+
+	tt := []struct {
+		name    string
+		options []bartender.Option[bartender.BarWriterConfig]
+		want    []string
+	}{
+		{name: "Exact Prices", want: []string{"100.125", "101.5", "99.875", "100.5", "100.3125"}},
+		{
+			name:    "Fixed Precision",
+			options: []bartender.Option[bartender.BarWriterConfig]{bartender.WithPricePrecision(1)},
+			want:    []string{"100.1", "101.5", "99.9", "100.5", "100.3"},
+		},
+		{
+			name: "Symbol Precision",
+			options: []bartender.Option[bartender.BarWriterConfig]{
+				bartender.WithSymbolPrecision(bartender.Precision{"AAPL": 2}),
+				bartender.WithPricePrecision(1),
+			},
+			want: []string{"100.13", "101.50", "99.88", "100.50", "100.31"},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			w, err := bartender.NewBarWriter(&buf, tc.options...)
+			if err != nil {
+				t.Fatalf("NewBarWriter() error = %v", err)
+			}
+
+			for range 2 {
+				if err := w.Write(bar); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+			}
+
+			if err := w.Flush(); err != nil {
+				t.Fatalf("Flush() error = %v", err)
+			}
+
+			records, err := csv.NewReader(&buf).ReadAll()
+			if err != nil {
+				t.Fatalf("ReadAll() error = %v", err)
+			}
+
+			if len(records) != 3 {
+				t.Fatalf("got %d records, want a header and 2 bars", len(records))
+			}
+
+			if records[0][0] != "symbol" || records[0][11] != "vwap" {
+				t.Errorf("header = %v", records[0])
+			}
+
+			record := records[1]
+			if diff := cmp.Diff(tc.want, []string{record[2], record[3], record[4], record[5], record[11]}); diff != "" {
+				t.Errorf("prices (-want +got):\n%s", diff)
+			}
+
+			// the bars can be read back
+			var got bartender.Bar
+			if err := got.UnmarshalCSV(record); err != nil {
+				t.Errorf("UnmarshalCSV() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestBarWriter_NoBars(t *testing.T) {
+	var buf bytes.Buffer
+
+	w, err := bartender.NewBarWriter(&buf)
+	if err != nil {
+		t.Fatalf("NewBarWriter() error = %v", err)
+	}
+
+	// flushing twice writes the header once
+	for range 2 {
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush() error = %v", err)
+		}
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+
+	if len(records) != 1 || records[0][0] != "symbol" {
+		t.Errorf("records = %v, want only the header", records)
+	}
+}
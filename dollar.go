@@ -8,6 +8,8 @@ Licensed under the MIT license. See LICENSE file in the project root for details
 package bartender
 
 import (
+	"context"
+
 	decimal "github.com/alpacahq/alpacadecimal"
 )
 
@@ -18,10 +20,16 @@ func WithDollarThreshold(threshold float64) Option[DollarBarConfig] {
 }
 
 type DollarBarConfig struct {
+	processorConfig
+
 	dollarThreshold decimal.Decimal `validate:"required"`
 }
 
 func (c DollarBarConfig) Process(trades <-chan Trade) chan *Bar {
+	return c.ProcessContext(context.Background(), trades)
+}
+
+func (c DollarBarConfig) ProcessContext(ctx context.Context, trades <-chan Trade) chan *Bar {
 	output := make(chan *Bar)
 
 	go func() {
@@ -30,16 +38,26 @@ func (c DollarBarConfig) Process(trades <-chan Trade) chan *Bar {
 		var current *Bar
 		var dollar decimal.Decimal
 
-		for trade := range trades {
+		// resume from the checkpoint, if any
+		var state accumulatorState
+		if c.restore(&state) {
+			current, dollar = state.Current.bar(), state.Value
+		}
+
+		partial := c.partials()
+
+		for trade := range c.newTrades(ctx, trades) {
 			if current == nil {
 				current = &Bar{}
 			}
 
-			// check if the trade is on a new day
-			if !current.Start.IsZero() && current.Start.Weekday() != trade.Time.Weekday() {
+			// check if the trade is in a new session
+			if c.session.crossed(current.Start, trade.Time) {
 				finalizedBar := current
 
-				output <- finalizedBar
+				if !c.closeBar(ctx, output, finalizedBar, CloseReasonSession) {
+					return
+				}
 
 				// reset the current bar
 				current = &Bar{}
@@ -47,7 +65,7 @@ func (c DollarBarConfig) Process(trades <-chan Trade) chan *Bar {
 				dollar = decimal.Zero
 			}
 
-			current.applyTrade(trade)
+			c.apply(current, trade)
 
 			// increment tracker
 			dollar = dollar.Add(trade.Price.Mul(trade.Size))
@@ -55,17 +73,25 @@ func (c DollarBarConfig) Process(trades <-chan Trade) chan *Bar {
 			if dollar.GreaterThanOrEqual(c.dollarThreshold) {
 				finalizedBar := current
 
-				output <- finalizedBar
+				if !c.closeBar(ctx, output, finalizedBar, CloseReasonThreshold) {
+					return
+				}
 
 				// reset the current bar
 				current = nil
 				// reset the dollar tracker
 				dollar = decimal.Zero
 			}
+
+			if !partial.publish(ctx, output, trade, current) {
+				return
+			}
+
+			c.save(trade, accumulatorState{Current: snapshotBar(current), Value: dollar})
 		}
 
 		if current != nil {
-			output <- current
+			c.closeBar(ctx, output, current, CloseReasonEndOfStream)
 		}
 	}()
 
@@ -79,10 +105,16 @@ func WithDollarImbalanceThreshold(threshold float64) Option[DollarImbalanceBarCo
 }
 
 type DollarImbalanceBarConfig struct {
+	processorConfig
+
 	imbalanceThreshold decimal.Decimal `validate:"required"`
 }
 
 func (c DollarImbalanceBarConfig) Process(trades <-chan Trade) chan *Bar {
+	return c.ProcessContext(context.Background(), trades)
+}
+
+func (c DollarImbalanceBarConfig) ProcessContext(ctx context.Context, trades <-chan Trade) chan *Bar {
 	output := make(chan *Bar)
 
 	go func() {
@@ -92,7 +124,15 @@ func (c DollarImbalanceBarConfig) Process(trades <-chan Trade) chan *Bar {
 		var netImbalance decimal.Decimal
 		var prevPrice decimal.Decimal
 
-		for trade := range trades {
+		// resume from the checkpoint, if any
+		var state accumulatorState
+		if c.restore(&state) {
+			current, netImbalance, prevPrice = state.Current.bar(), state.Value, state.PrevPrice
+		}
+
+		partial := c.partials()
+
+		for trade := range c.newTrades(ctx, trades) {
 			if prevPrice.IsZero() {
 				prevPrice = trade.Price
 			}
@@ -101,11 +141,13 @@ func (c DollarImbalanceBarConfig) Process(trades <-chan Trade) chan *Bar {
 				current = &Bar{}
 			}
 
-			// check if the trade is on a new day
-			if !current.Start.IsZero() && current.Start.Weekday() != trade.Time.Weekday() {
+			// check if the trade is in a new session
+			if c.session.crossed(current.Start, trade.Time) {
 				finalizedBar := current
 
-				output <- finalizedBar
+				if !c.closeBar(ctx, output, finalizedBar, CloseReasonSession) {
+					return
+				}
 
 				// reset the current bar
 				current = &Bar{}
@@ -113,7 +155,7 @@ func (c DollarImbalanceBarConfig) Process(trades <-chan Trade) chan *Bar {
 				netImbalance = decimal.Zero
 			}
 
-			current.applyTrade(trade)
+			c.apply(current, trade)
 
 			// update net imbalance
 			if trade.Price.GreaterThan(prevPrice) {
@@ -127,17 +169,25 @@ func (c DollarImbalanceBarConfig) Process(trades <-chan Trade) chan *Bar {
 			if netImbalance.Abs().GreaterThanOrEqual(c.imbalanceThreshold) {
 				finalizedBar := current
 
-				output <- finalizedBar
+				if !c.closeBar(ctx, output, finalizedBar, CloseReasonThreshold) {
+					return
+				}
 
 				// reset the current bar
 				current = nil
 				// reset the net imbalance
 				netImbalance = decimal.Zero
 			}
+
+			if !partial.publish(ctx, output, trade, current) {
+				return
+			}
+
+			c.save(trade, accumulatorState{Current: snapshotBar(current), Value: netImbalance, PrevPrice: prevPrice})
 		}
 
 		if current != nil {
-			output <- current
+			c.closeBar(ctx, output, current, CloseReasonEndOfStream)
 		}
 	}()
 
@@ -151,10 +201,16 @@ func WithDollarRunThreshold(dollarThreshold float64) Option[DollarRunBarConfig]
 }
 
 type DollarRunBarConfig struct {
+	processorConfig
+
 	runDollarThreshold decimal.Decimal `validate:"required,nonzero"`
 }
 
 func (c DollarRunBarConfig) Process(trades <-chan Trade) chan *Bar {
+	return c.ProcessContext(context.Background(), trades)
+}
+
+func (c DollarRunBarConfig) ProcessContext(ctx context.Context, trades <-chan Trade) chan *Bar {
 	output := make(chan *Bar)
 
 	go func() {
@@ -164,7 +220,15 @@ func (c DollarRunBarConfig) Process(trades <-chan Trade) chan *Bar {
 		var upwardDollarRun, downwardDollarRun decimal.Decimal
 		var prevPrice decimal.Decimal
 
-		for trade := range trades {
+		// resume from the checkpoint, if any
+		var state runState
+		if c.restore(&state) {
+			current, upwardDollarRun, downwardDollarRun, prevPrice = state.Current.bar(), state.Up, state.Down, state.PrevPrice
+		}
+
+		partial := c.partials()
+
+		for trade := range c.newTrades(ctx, trades) {
 			// initialize the last price if not already set
 			if prevPrice.IsZero() {
 				prevPrice = trade.Price
@@ -174,11 +238,13 @@ func (c DollarRunBarConfig) Process(trades <-chan Trade) chan *Bar {
 				current = &Bar{}
 			}
 
-			// check if the trade is on a new day
-			if !current.Start.IsZero() && current.Start.Weekday() != trade.Time.Weekday() {
+			// check if the trade is in a new session
+			if c.session.crossed(current.Start, trade.Time) {
 				finalizedBar := current
 
-				output <- finalizedBar
+				if !c.closeBar(ctx, output, finalizedBar, CloseReasonSession) {
+					return
+				}
 
 				// reset the current bar
 				current = &Bar{}
@@ -188,7 +254,7 @@ func (c DollarRunBarConfig) Process(trades <-chan Trade) chan *Bar {
 				downwardDollarRun = decimal.Zero
 			}
 
-			current.applyTrade(trade)
+			c.apply(current, trade)
 
 			// calculate the dollar value of the trade (Price * Size)
 			tradeDollarValue := trade.Price.Mul(trade.Size)
@@ -208,7 +274,9 @@ func (c DollarRunBarConfig) Process(trades <-chan Trade) chan *Bar {
 			if upwardDollarRun.Abs().GreaterThanOrEqual(c.runDollarThreshold) || downwardDollarRun.Abs().GreaterThanOrEqual(c.runDollarThreshold) {
 				finalizedBar := current
 
-				output <- finalizedBar
+				if !c.closeBar(ctx, output, finalizedBar, CloseReasonThreshold) {
+					return
+				}
 
 				// reset the current bar
 				current = nil
@@ -217,17 +285,92 @@ func (c DollarRunBarConfig) Process(trades <-chan Trade) chan *Bar {
 				// reset the dollar runs
 				downwardDollarRun = decimal.Zero
 			}
+
+			if !partial.publish(ctx, output, trade, current) {
+				return
+			}
+
+			c.save(trade, runState{Current: snapshotBar(current), Up: upwardDollarRun, Down: downwardDollarRun, PrevPrice: prevPrice})
 		}
 
 		if current != nil {
-			output <- current
+			c.closeBar(ctx, output, current, CloseReasonEndOfStream)
 		}
 	}()
 
 	return output
 }
 
+// WithAdaptiveDollarImbalance configures a dollar imbalance bar whose threshold adapts to previous bars. warmup is the
+// expected number of ticks per bar until the first bar closes and span the span of the moving averages.
+func WithAdaptiveDollarImbalance(warmup, span int64) Option[AdaptiveDollarImbalanceBarConfig] {
+	return func(c *AdaptiveDollarImbalanceBarConfig) {
+		c.warmup = warmup
+		c.span = span
+	}
+}
+
+// WithAdaptiveDollarImbalanceLimits clamps the expected number of ticks per bar.
+func WithAdaptiveDollarImbalanceLimits(minTicks, maxTicks int64) Option[AdaptiveDollarImbalanceBarConfig] {
+	return func(c *AdaptiveDollarImbalanceBarConfig) {
+		c.minTicks = minTicks
+		c.maxTicks = maxTicks
+	}
+}
+
+// AdaptiveDollarImbalanceBarConfig closes a bar once the signed dollar imbalance exceeds the expected imbalance,
+// estimated from previous bars.
+type AdaptiveDollarImbalanceBarConfig struct {
+	adaptiveConfig
+}
+
+func (c AdaptiveDollarImbalanceBarConfig) Process(trades <-chan Trade) chan *Bar {
+	return c.ProcessContext(context.Background(), trades)
+}
+
+func (c AdaptiveDollarImbalanceBarConfig) ProcessContext(ctx context.Context, trades <-chan Trade) chan *Bar {
+	return c.processImbalance(ctx, trades, dollarMeasure)
+}
+
+// WithAdaptiveDollarRun configures a dollar run bar whose threshold adapts to previous bars. warmup is the expected
+// number of ticks per bar until the first bar closes and span the span of the moving averages.
+func WithAdaptiveDollarRun(warmup, span int64) Option[AdaptiveDollarRunBarConfig] {
+	return func(c *AdaptiveDollarRunBarConfig) {
+		c.warmup = warmup
+		c.span = span
+	}
+}
+
+// WithAdaptiveDollarRunLimits clamps the expected number of ticks per bar.
+func WithAdaptiveDollarRunLimits(minTicks, maxTicks int64) Option[AdaptiveDollarRunBarConfig] {
+	return func(c *AdaptiveDollarRunBarConfig) {
+		c.minTicks = minTicks
+		c.maxTicks = maxTicks
+	}
+}
+
+// AdaptiveDollarRunBarConfig closes a bar once the larger of the buy and sell dollar value in the bar exceeds the
+// expected run, estimated from previous bars.
+type AdaptiveDollarRunBarConfig struct {
+	adaptiveConfig
+}
+
+func (c AdaptiveDollarRunBarConfig) Process(trades <-chan Trade) chan *Bar {
+	return c.ProcessContext(context.Background(), trades)
+}
+
+func (c AdaptiveDollarRunBarConfig) ProcessContext(ctx context.Context, trades <-chan Trade) chan *Bar {
+	return c.processRuns(ctx, trades, dollarMeasure)
+}
+
 // Interface guards
 var _ Processor = (*DollarBarConfig)(nil)
 var _ Processor = (*DollarImbalanceBarConfig)(nil)
 var _ Processor = (*DollarRunBarConfig)(nil)
+var _ Processor = (*AdaptiveDollarImbalanceBarConfig)(nil)
+var _ Processor = (*AdaptiveDollarRunBarConfig)(nil)
+var _ ContextProcessor = (*DollarBarConfig)(nil)
+var _ ContextProcessor = (*DollarImbalanceBarConfig)(nil)
+var _ ContextProcessor = (*DollarRunBarConfig)(nil)
+var _ ContextProcessor = (*AdaptiveDollarImbalanceBarConfig)(nil)
+var _ ContextProcessor = (*AdaptiveDollarRunBarConfig)(nil)
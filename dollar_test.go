@@ -224,3 +224,151 @@ func TestDollarRunBarConfig_Process(t *testing.T) {
 		tc.Run(t, p)
 	}
 }
+
+func TestAdaptiveDollarImbalanceBarConfig_Process(t *testing.T) {
+	type adaptiveInput struct {
+		warmup, span, minTicks, maxTicks int64
+	}
+
+	tt := []TestCase[adaptiveInput]{
+		{
+			name:  "Expected Imbalance Trigger",
+			input: adaptiveInput{warmup: 2, span: 2},
+			trades: []bartender.Trade{
+				{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)},
+				{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 10, 0, time.UTC)},
+				{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 20, 0, time.UTC)},
+				{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 30, 0, time.UTC)},
+				{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 40, 0, time.UTC)},
+			},
+			want: []bartender.Bar{
+				{
+					Open:      decimal.NewFromInt(100),
+					High:      decimal.NewFromInt(100),
+					Low:       decimal.NewFromInt(100),
+					Close:     decimal.NewFromInt(100),
+					Volume:    decimal.NewFromInt(2),
+					Start:     time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+					BuyVolume: decimal.NewFromInt(2),
+					Ticks:     2,
+					Threshold: decimal.NewFromFloat(200),
+				},
+				{
+					Open:      decimal.NewFromInt(100),
+					High:      decimal.NewFromInt(100),
+					Low:       decimal.NewFromInt(100),
+					Close:     decimal.NewFromInt(100),
+					Volume:    decimal.NewFromInt(2),
+					Start:     time.Date(2025, 1, 1, 10, 0, 20, 0, time.UTC),
+					BuyVolume: decimal.NewFromInt(2),
+					Ticks:     2,
+					Threshold: decimal.NewFromFloat(200),
+				},
+				{
+					Open:      decimal.NewFromInt(100),
+					High:      decimal.NewFromInt(100),
+					Low:       decimal.NewFromInt(100),
+					Close:     decimal.NewFromInt(100),
+					Volume:    decimal.NewFromInt(1),
+					Start:     time.Date(2025, 1, 1, 10, 0, 40, 0, time.UTC),
+					BuyVolume: decimal.NewFromInt(1),
+					Ticks:     1,
+					Threshold: decimal.NewFromFloat(200),
+				},
+			},
+		},
+		{
+			name:   "No Trades",
+			input:  adaptiveInput{warmup: 2},
+			trades: []bartender.Trade{},
+			want:   []bartender.Bar{},
+		},
+	}
+
+	for _, tc := range tt {
+		p, err := bartender.New(
+			bartender.WithAdaptiveDollarImbalance(tc.input.warmup, tc.input.span),
+			bartender.WithAdaptiveDollarImbalanceLimits(tc.input.minTicks, tc.input.maxTicks),
+		)
+		if err != nil {
+			t.Errorf("New() error = %v", err)
+			return
+		}
+
+		tc.Run(t, p)
+	}
+}
+
+func TestAdaptiveDollarRunBarConfig_Process(t *testing.T) {
+	type adaptiveInput struct {
+		warmup, span, minTicks, maxTicks int64
+	}
+
+	tt := []TestCase[adaptiveInput]{
+		{
+			name:  "Expected Run Trigger",
+			input: adaptiveInput{warmup: 2, span: 2},
+			trades: []bartender.Trade{
+				{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)},
+				{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 10, 0, time.UTC)},
+				{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 20, 0, time.UTC)},
+				{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 30, 0, time.UTC)},
+				{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 40, 0, time.UTC)},
+			},
+			want: []bartender.Bar{
+				{
+					Open:      decimal.NewFromInt(100),
+					High:      decimal.NewFromInt(100),
+					Low:       decimal.NewFromInt(100),
+					Close:     decimal.NewFromInt(100),
+					Volume:    decimal.NewFromInt(2),
+					Start:     time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+					BuyVolume: decimal.NewFromInt(2),
+					Ticks:     2,
+					Threshold: decimal.NewFromFloat(200),
+				},
+				{
+					Open:      decimal.NewFromInt(100),
+					High:      decimal.NewFromInt(100),
+					Low:       decimal.NewFromInt(100),
+					Close:     decimal.NewFromInt(100),
+					Volume:    decimal.NewFromInt(2),
+					Start:     time.Date(2025, 1, 1, 10, 0, 20, 0, time.UTC),
+					BuyVolume: decimal.NewFromInt(2),
+					Ticks:     2,
+					Threshold: decimal.NewFromFloat(200),
+				},
+				{
+					Open:      decimal.NewFromInt(100),
+					High:      decimal.NewFromInt(100),
+					Low:       decimal.NewFromInt(100),
+					Close:     decimal.NewFromInt(100),
+					Volume:    decimal.NewFromInt(1),
+					Start:     time.Date(2025, 1, 1, 10, 0, 40, 0, time.UTC),
+					BuyVolume: decimal.NewFromInt(1),
+					Ticks:     1,
+					Threshold: decimal.NewFromFloat(200),
+				},
+			},
+		},
+		{
+			name:   "No Trades",
+			input:  adaptiveInput{warmup: 2},
+			trades: []bartender.Trade{},
+			want:   []bartender.Bar{},
+		},
+	}
+
+	for _, tc := range tt {
+		p, err := bartender.New(
+			bartender.WithAdaptiveDollarRun(tc.input.warmup, tc.input.span),
+			bartender.WithAdaptiveDollarRunLimits(tc.input.minTicks, tc.input.maxTicks),
+		)
+		if err != nil {
+			t.Errorf("New() error = %v", err)
+			return
+		}
+
+		tc.Run(t, p)
+	}
+}
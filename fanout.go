@@ -0,0 +1,134 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// TaggedBar is a Bar along with the tag of the processor that produced it.
+type TaggedBar struct {
+	Tag string `json:"tag"`
+	Bar
+}
+
+// GenerateMulti processes trades synchronously with every processor, keyed by tag. Trades pass through the filters
+// once and the bars of each processor are returned under its tag.
+func GenerateMulti(trades []Trade, processors map[string]Processor, filters ...FilterFunc) (map[string][]Bar, error) {
+	if len(trades) == 0 {
+		return nil, fmt.Errorf("no trades provided")
+	}
+
+	tradesCh := make(chan Trade)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		defer close(tradesCh)
+		for _, trade := range trades {
+			if !send(ctx, tradesCh, trade) {
+				return
+			}
+		}
+	}()
+
+	tagged, errs, err := GenerateMultiStreamContext(ctx, tradesCh, processors, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	bars := make(map[string][]Bar, len(processors))
+	for bar := range tagged {
+		bars[bar.Tag] = append(bars[bar.Tag], bar.Bar)
+	}
+
+	return bars, <-errs
+}
+
+// GenerateMultiStream processes a channel of trades with every processor, keyed by tag, and returns their completed
+// bars tagged with the processor that produced them on a single response channel.
+func GenerateMultiStream(trades chan Trade, processors map[string]Processor, filters ...FilterFunc) (<-chan TaggedBar, error) {
+	bars, _, err := GenerateMultiStreamContext(context.Background(), trades, processors, filters...)
+
+	return bars, err
+}
+
+// GenerateMultiStreamContext processes a channel of trades with every processor, keyed by tag, until the trades
+// channel is closed or the context is cancelled. Trades pass through the filters once and are then handed to each
+// processor in turn, so a processor whose bars are not being received holds back the others. The bars of a
+// processor keep their order, while the bars of different processors may interleave.
+//
+// Cancellation and errors are reported as by GenerateStreamContext.
+func GenerateMultiStreamContext(ctx context.Context, trades chan Trade, processors map[string]Processor, filters ...FilterFunc) (<-chan TaggedBar, <-chan error, error) {
+	if trades == nil {
+		return nil, nil, fmt.Errorf("trades channel is nil")
+	}
+
+	if len(processors) == 0 {
+		return nil, nil, fmt.Errorf("no processors provided")
+	}
+
+	bars := make(chan TaggedBar)
+	errs := make(chan error, 1)
+
+	ctx, cancel := withAbort(ctx)
+
+	go func(trades chan Trade) {
+		defer close(errs)
+		defer close(bars)
+		defer cancel(nil)
+
+		// apply the filter to the trades channel
+		filteredTradesStream := trades
+		for _, f := range filters {
+			filteredTradesStream = FilterContext(ctx, f)(filteredTradesStream)
+		}
+
+		var wg sync.WaitGroup
+
+		inputs := make([]chan Trade, 0, len(processors))
+		for tag, processor := range processors {
+			input := make(chan Trade)
+			inputs = append(inputs, input)
+
+			wg.Add(1)
+			go func(tag string, output chan *Bar) {
+				defer wg.Done()
+
+				for bar := range output {
+					if bar != nil {
+						send(ctx, bars, TaggedBar{Tag: tag, Bar: *bar})
+					}
+				}
+			}(tag, process(ctx, processor, input))
+		}
+
+		// hand every trade to each processor
+		for trade := range receive(ctx, filteredTradesStream) {
+			for _, input := range inputs {
+				if !send(ctx, input, trade) {
+					break
+				}
+			}
+		}
+
+		for _, input := range inputs {
+			close(input)
+		}
+
+		wg.Wait()
+
+		if ctx.Err() != nil {
+			errs <- context.Cause(ctx)
+		}
+	}(trades)
+
+	return bars, errs, nil
+}
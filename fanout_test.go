@@ -0,0 +1,116 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender_test
+
+import (
+	"testing"
+	"time"
+
+	decimal "github.com/alpacahq/alpacadecimal"
+	"github.com/csgriffis/bartender"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func fanoutTrades() []bartender.Trade {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	return []bartender.Trade{
+		{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: start},
+		{Price: decimal.NewFromInt(101), Size: decimal.NewFromInt(2), Side: bartender.SideBuy, Time: start.Add(20 * time.Second)},
+		{Price: decimal.NewFromInt(99), Size: decimal.NewFromInt(3), Side: bartender.SideSell, Time: start.Add(40 * time.Second)},
+		{Price: decimal.NewFromInt(102), Size: decimal.NewFromInt(4), Side: bartender.SideBuy, Time: start.Add(70 * time.Second)},
+	}
+}
+
+func fanoutProcessors(t *testing.T) map[string]bartender.Processor {
+	t.Helper()
+
+	timeBars, err := bartender.New(bartender.WithInterval(time.Minute))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tickBars, err := bartender.New(bartender.WithTickThreshold(2))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	volumeBars, err := bartender.New(bartender.WithVolumeThreshold(5))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	return map[string]bartender.Processor{"time": timeBars, "tick": tickBars, "volume": volumeBars}
+}
+
+func TestGenerateMulti(t *testing.T) {
+	trades := fanoutTrades()
+	processors := fanoutProcessors(t)
+
+	// each processor produces the same bars as it would on its own
+	want := make(map[string][]bartender.Bar)
+	for tag, processor := range processors {
+		bars, err := bartender.Generate(trades, processor)
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+
+		want[tag] = bars
+	}
+
+	// the filter is only applied once
+	var filtered int
+	countTrades := func(trade bartender.Trade) bool {
+		filtered++
+		return true
+	}
+
+	got, err := bartender.GenerateMulti(trades, processors, countTrades)
+	if err != nil {
+		t.Fatalf("GenerateMulti() error = %v", err)
+	}
+
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreUnexported(bartender.Bar{}), ignoreBarStats); diff != "" {
+		t.Errorf("GenerateMulti() mismatch (-want +got):\n%s", diff)
+	}
+
+	if filtered != len(trades) {
+		t.Errorf("filter called %d times, want %d", filtered, len(trades))
+	}
+}
+
+func TestGenerateMultiStream(t *testing.T) {
+	trades := fanoutTrades()
+
+	tradesCh := make(chan bartender.Trade)
+	go func() {
+		defer close(tradesCh)
+		for _, trade := range trades {
+			tradesCh <- trade
+		}
+	}()
+
+	bars, err := bartender.GenerateMultiStream(tradesCh, fanoutProcessors(t))
+	if err != nil {
+		t.Fatalf("GenerateMultiStream() error = %v", err)
+	}
+
+	counts := make(map[string]int)
+	for bar := range bars {
+		counts[bar.Tag]++
+	}
+
+	if diff := cmp.Diff(map[string]int{"time": 2, "tick": 2, "volume": 2}, counts); diff != "" {
+		t.Errorf("GenerateMultiStream() bar counts mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := bartender.GenerateMultiStream(make(chan bartender.Trade), nil); err == nil {
+		t.Errorf("GenerateMultiStream() error = nil, want error for no processors")
+	}
+}
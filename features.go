@@ -0,0 +1,158 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender
+
+import (
+	"math"
+)
+
+// Features are microstructure features of a bar, computed from its trades as they are applied. Price changes and
+// returns are measured between consecutive trades in the bar, so a bar of a single trade only has the features that
+// do not depend on them.
+type Features struct {
+	// RollSpread is Roll's estimate of the effective spread, 2√(-cov(Δpₜ, Δpₜ₋₁)), or zero when the covariance of
+	// consecutive price changes is not negative.
+	RollSpread float64 `json:"roll_spread"`
+
+	// KyleLambda is the slope of the regression of price changes on the signed volume of the trades making them.
+	KyleLambda float64 `json:"kyle_lambda"`
+
+	// Amihud is the absolute log return of the bar per unit of notional, |ln(Close/Open)| / Notional.
+	Amihud float64 `json:"amihud"`
+
+	// VPIN is the bar's contribution to the volume-synchronized probability of informed trading,
+	// |BuyVolume - SellVolume| / Volume. Averaging it over consecutive volume bars gives VPIN.
+	VPIN float64 `json:"vpin"`
+
+	// OFI is the order flow imbalance of the bar, BuyVolume - SellVolume.
+	OFI float64 `json:"ofi"`
+
+	// RealizedVariance is the sum of the squared log returns between consecutive trades.
+	RealizedVariance float64 `json:"realized_variance"`
+}
+
+// WithFeatures computes the microstructure Features of every bar the processor emits. Bars that carry no trades, such
+// as the empty bars filling gaps between time bars, have no Features. The processor type must be given explicitly,
+// e.g. WithFeatures[VolumeBarConfig]().
+func WithFeatures[T any, PT configurable[T]]() Option[T] {
+	return func(c *T) {
+		PT(c).setFeatures()
+	}
+}
+
+func (c *processorConfig) setFeatures() {
+	c.features = true
+}
+
+// apply applies the trade to the bar, computing its features if the processor is configured WithFeatures.
+func (c processorConfig) apply(bar *Bar, trade Trade) {
+	if c.features && bar.features == nil {
+		bar.features = &featureAccumulator{}
+	}
+
+	bar.applyTrade(trade)
+}
+
+// featureAccumulator holds the running sums the Features of a bar are computed from. Its fields are exported so that
+// checkpoints can serialize it.
+type featureAccumulator struct {
+	LastPrice  float64 `json:"last_price"`
+	LastChange float64 `json:"last_change"`
+	Changes    int     `json:"changes"`
+
+	// sums over consecutive pairs of price changes, for the Roll spread
+	SumChange        float64 `json:"sum_change"`
+	SumPrevChange    float64 `json:"sum_prev_change"`
+	SumChangeProduct float64 `json:"sum_change_product"`
+
+	// sums over price changes and the signed volume of the trades making them, for Kyle's lambda
+	SumFlow        float64 `json:"sum_flow"`
+	SumFlowSquared float64 `json:"sum_flow_squared"`
+	SumFlowChange  float64 `json:"sum_flow_change"`
+	SumAllChange   float64 `json:"sum_all_change"`
+
+	SumSquaredReturn float64 `json:"sum_squared_return"`
+}
+
+// add updates the sums with the trade.
+func (a *featureAccumulator) add(t Trade) {
+	price := t.Price.InexactFloat64()
+	last := a.LastPrice
+	a.LastPrice = price
+
+	if last <= 0 || price <= 0 {
+		return
+	}
+
+	change := price - last
+
+	if a.Changes > 0 {
+		a.SumChange += change
+		a.SumPrevChange += a.LastChange
+		a.SumChangeProduct += change * a.LastChange
+	}
+
+	flow := signedSize(t)
+	a.SumFlow += flow
+	a.SumFlowSquared += flow * flow
+	a.SumFlowChange += flow * change
+	a.SumAllChange += change
+
+	r := math.Log(price / last)
+	a.SumSquaredReturn += r * r
+
+	a.LastChange = change
+	a.Changes++
+}
+
+// features computes the features of the bar the sums were accumulated for.
+func (a *featureAccumulator) features(b *Bar) *Features {
+	f := &Features{RealizedVariance: a.SumSquaredReturn}
+
+	if pairs := float64(a.Changes - 1); pairs > 0 {
+		cov := a.SumChangeProduct/pairs - (a.SumChange/pairs)*(a.SumPrevChange/pairs)
+		if cov < 0 {
+			f.RollSpread = 2 * math.Sqrt(-cov)
+		}
+	}
+
+	if n := float64(a.Changes); n > 0 {
+		variance := a.SumFlowSquared/n - (a.SumFlow/n)*(a.SumFlow/n)
+		if variance > 0 {
+			f.KyleLambda = (a.SumFlowChange/n - (a.SumFlow/n)*(a.SumAllChange/n)) / variance
+		}
+	}
+
+	if notional := b.Notional.InexactFloat64(); notional > 0 && b.Open.IsPositive() && b.Close.IsPositive() {
+		f.Amihud = math.Abs(math.Log(b.Close.InexactFloat64()/b.Open.InexactFloat64())) / notional
+	}
+
+	imbalance := b.BuyVolume.Sub(b.SellVolume).InexactFloat64()
+	f.OFI = imbalance
+
+	if volume := b.Volume.InexactFloat64(); volume > 0 {
+		f.VPIN = math.Abs(imbalance) / volume
+	}
+
+	return f
+}
+
+// signedSize returns the size of the trade, positive for buys and negative for sells. Trades of unknown side are
+// split by their buy ratio, as for the buy and sell volume of a bar.
+func signedSize(t Trade) float64 {
+	size := t.Size.InexactFloat64()
+
+	switch t.Side {
+	case SideBuy:
+		return size
+	case SideSell:
+		return -size
+	}
+
+	return size * (2*t.BuyRatio.InexactFloat64() - 1)
+}
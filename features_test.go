@@ -0,0 +1,100 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	decimal "github.com/alpacahq/alpacadecimal"
+	"github.com/csgriffis/bartender"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestWithFeatures(t *testing.T) {
+	trades := []bartender.Trade{
+		{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{Price: decimal.NewFromInt(101), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 10, 0, time.UTC)},
+		{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideSell, Time: time.Date(2025, 1, 1, 10, 0, 20, 0, time.UTC)},
+		{Price: decimal.NewFromInt(101), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 30, 0, time.UTC)},
+	}
+
+	r := math.Log(1.01)
+
+	tick, err := bartender.New(bartender.WithTickThreshold(4), bartender.WithFeatures[bartender.TickBarConfig]())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	timed, err := bartender.New(bartender.WithInterval(time.Minute), bartender.WithFeatures[bartender.TimeBarConfig]())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	plain, err := bartender.New(bartender.WithTickThreshold(4))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	want := &bartender.Features{
+		RollSpread:       2,
+		KyleLambda:       1,
+		Amihud:           r / 402,
+		VPIN:             0.5,
+		OFI:              2,
+		RealizedVariance: 3 * r * r,
+	}
+
+	tt := []struct {
+		name      string
+		processor bartender.Processor
+		want      *bartender.Features
+	}{
+		{"Tick Bars", tick, want},
+		{"Time Bars", timed, want},
+		{"Disabled", plain, nil},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			bars, err := bartender.Generate(trades, tc.processor)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			if len(bars) != 1 {
+				t.Fatalf("Generate() = %d bars, want 1", len(bars))
+			}
+
+			if diff := cmp.Diff(tc.want, bars[0].Features, cmpopts.EquateApprox(0, 1e-12)); diff != "" {
+				t.Errorf("Features (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWithFeatures_SingleTrade(t *testing.T) {
+	p, err := bartender.New(bartender.WithTickThreshold(1), bartender.WithFeatures[bartender.TickBarConfig]())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	bars, err := bartender.Generate([]bartender.Trade{
+		{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(2), BuyRatio: decimal.NewFromFloat(0.75), Time: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)},
+	}, p)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	// only the features of the bar's volume are defined for a single trade
+	if diff := cmp.Diff(&bartender.Features{VPIN: 0.5, OFI: 1}, bars[0].Features); diff != "" {
+		t.Errorf("Features (-want +got):\n%s", diff)
+	}
+}
@@ -7,19 +7,29 @@ Licensed under the MIT license. See LICENSE file in the project root for details
 
 package bartender
 
+import (
+	"context"
+)
+
 type FilterFunc func(Trade) bool
 
 // Filter returns a function that filters trades based on the provided filter function.
 func Filter(filter func(Trade) bool) func(trades chan Trade) chan Trade {
+	return FilterContext(context.Background(), filter)
+}
+
+// FilterContext returns a function that filters trades based on the provided filter function until the trades
+// channel is closed or the context is cancelled.
+func FilterContext(ctx context.Context, filter func(Trade) bool) func(trades chan Trade) chan Trade {
 	return func(trades chan Trade) chan Trade {
 		output := make(chan Trade)
 
 		go func() {
 			defer close(output)
 
-			for trade := range trades {
-				if filter(trade) {
-					output <- trade
+			for trade := range receive(ctx, trades) {
+				if filter(trade) && !send(ctx, output, trade) {
+					return
 				}
 			}
 		}()
@@ -27,3 +37,11 @@ func Filter(filter func(Trade) bool) func(trades chan Trade) chan Trade {
 		return output
 	}
 }
+
+// InSession returns a filter that keeps trades inside a calendar session of one of the given kinds, or of any kind
+// when none are given.
+func InSession(calendar *Calendar, kinds ...SessionKind) FilterFunc {
+	return func(t Trade) bool {
+		return calendar.IsOpen(t.Time, kinds...)
+	}
+}
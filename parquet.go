@@ -0,0 +1,627 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"time"
+
+	decimal "github.com/alpacahq/alpacadecimal"
+	"github.com/parquet-go/parquet-go"
+)
+
+const (
+	// defaultDecimalScale is the number of decimal places of decimal columns unless set by WithDecimalScale, enough
+	// for the prices and sizes of crypto pairs quoted in satoshis.
+	defaultDecimalScale = 8
+	// decimalPrecision is the number of digits of decimal columns, the most a 16 byte column holds.
+	decimalPrecision = 38
+	// decimalSize is the size in bytes of decimal columns.
+	decimalSize = 16
+)
+
+var (
+	// maxUnscaled bounds the unscaled values of decimal columns.
+	maxUnscaled = new(big.Int).Exp(big.NewInt(10), big.NewInt(decimalPrecision), nil)
+	// decimalModulus converts negative unscaled values to two's complement.
+	decimalModulus = new(big.Int).Lsh(big.NewInt(1), 8*decimalSize)
+)
+
+// WithDecimalScale sets the number of decimal places kept by the decimal columns of a Parquet writer, from 0 to 38.
+// Prices, sizes and volumes with more decimal places are rounded. Defaults to 8.
+func WithDecimalScale(scale int32) Option[ParquetWriterConfig] {
+	return func(c *ParquetWriterConfig) {
+		c.scale = scale
+	}
+}
+
+// ParquetWriterConfig configures a ParquetBarWriter or ParquetTradeWriter.
+type ParquetWriterConfig struct {
+	scale int32
+}
+
+// columnKind is the type of a Parquet column.
+type columnKind int
+
+const (
+	// columnString holds UTF-8 strings
+	columnString columnKind = iota
+	// columnDecimal holds decimals with the scale of the writer
+	columnDecimal
+	// columnTime holds nanosecond timestamps, null for the zero time
+	columnTime
+	// columnInt holds 64 bit integers
+	columnInt
+	// columnBool holds booleans
+	columnBool
+	// columnFloat holds optional doubles
+	columnFloat
+)
+
+// parquetColumn describes a column written by the Parquet writers.
+type parquetColumn struct {
+	name string
+	kind columnKind
+}
+
+// barParquetColumns are the columns of the bars written by a ParquetBarWriter, in the order it writes them.
+var barParquetColumns = []parquetColumn{
+	{"symbol", columnString},
+	{"start", columnTime},
+	{"open", columnDecimal},
+	{"high", columnDecimal},
+	{"low", columnDecimal},
+	{"close", columnDecimal},
+	{"volume", columnDecimal},
+	{"ticks", columnInt},
+	{"upticks", columnInt},
+	{"buy_volume", columnDecimal},
+	{"sell_volume", columnDecimal},
+	{"vwap", columnDecimal},
+	{"notional", columnDecimal},
+	{"end", columnTime},
+	{"last_trade_time", columnTime},
+	{"downticks", columnInt},
+	{"threshold", columnDecimal},
+	{"provisional", columnBool},
+	{"revision", columnInt},
+	{"processor", columnString},
+	{"sequence", columnInt},
+	{"close_reason", columnString},
+	{"roll_spread", columnFloat},
+	{"kyle_lambda", columnFloat},
+	{"amihud", columnFloat},
+	{"vpin", columnFloat},
+	{"ofi", columnFloat},
+	{"realized_variance", columnFloat},
+}
+
+// tradeParquetColumns are the columns of the trades written by a ParquetTradeWriter, in the order it writes them.
+var tradeParquetColumns = []parquetColumn{
+	{"symbol", columnString},
+	{"time", columnTime},
+	{"price", columnDecimal},
+	{"size", columnDecimal},
+	{"side", columnString},
+	{"id", columnString},
+	{"action", columnString},
+	{"buy_ratio", columnDecimal},
+}
+
+// parquetSchema returns the schema of the columns, in order, with decimals of the scale.
+func parquetSchema(name string, columns []parquetColumn, scale int32) *parquet.Schema {
+	fields := make([]reflect.StructField, len(columns))
+
+	for i, column := range columns {
+		var goType reflect.Type
+		var tag string
+
+		switch column.kind {
+		case columnString:
+			goType = reflect.TypeFor[string]()
+		case columnDecimal:
+			goType = reflect.TypeFor[[decimalSize]byte]()
+			tag = fmt.Sprintf(",decimal(%d:%d)", scale, decimalPrecision)
+		case columnTime:
+			goType = reflect.TypeFor[int64]()
+			tag = ",timestamp(nanosecond),optional"
+		case columnInt:
+			goType = reflect.TypeFor[int64]()
+		case columnBool:
+			goType = reflect.TypeFor[bool]()
+		case columnFloat:
+			goType = reflect.TypeFor[float64]()
+			tag = ",optional"
+		}
+
+		fields[i] = reflect.StructField{
+			Name: fmt.Sprintf("Column%d", i),
+			Type: goType,
+			Tag:  reflect.StructTag(fmt.Sprintf(`parquet:"%s%s"`, column.name, tag)),
+		}
+	}
+
+	// a struct keeps the columns in order, unlike a parquet.Group
+	model := reflect.New(reflect.StructOf(fields)).Elem().Interface()
+
+	return parquet.NewSchema(name, parquet.SchemaOf(model))
+}
+
+// parquetWriter writes rows of the columns to a Parquet file.
+type parquetWriter struct {
+	writer *parquet.Writer
+	scale  int32
+}
+
+// newParquetWriter creates a writer of the columns to w.
+func newParquetWriter(w io.Writer, name string, columns []parquetColumn, options []Option[ParquetWriterConfig]) (*parquetWriter, error) {
+	cfg, err := configure(append([]Option[ParquetWriterConfig]{WithDecimalScale(defaultDecimalScale)}, options...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.scale < 0 || cfg.scale > decimalPrecision {
+		return nil, fmt.Errorf("decimal scale %d is outside 0 to %d", cfg.scale, decimalPrecision)
+	}
+
+	schema := parquetSchema(name, columns, cfg.scale)
+
+	return &parquetWriter{writer: parquet.NewWriter(w, schema), scale: cfg.scale}, nil
+}
+
+// write writes the row built by fill.
+func (w *parquetWriter) write(fill func(*rowBuilder)) error {
+	b := rowBuilder{scale: w.scale}
+	fill(&b)
+
+	if b.err != nil {
+		return b.err
+	}
+
+	_, err := w.writer.WriteRows([]parquet.Row{b.row})
+
+	return err
+}
+
+// rowBuilder appends the values of a row in column order, keeping the first error.
+type rowBuilder struct {
+	row   parquet.Row
+	scale int32
+	err   error
+}
+
+func (b *rowBuilder) append(value parquet.Value, defined bool) {
+	definition := 0
+	if defined {
+		definition = 1
+	}
+
+	b.row = append(b.row, value.Level(0, definition, len(b.row)))
+}
+
+func (b *rowBuilder) string(s string) {
+	b.append(parquet.ByteArrayValue([]byte(s)), false)
+}
+
+func (b *rowBuilder) decimal(d decimal.Decimal) {
+	unscaled := d.Round(b.scale).Shift(b.scale).BigInt()
+	if unscaled.CmpAbs(maxUnscaled) >= 0 {
+		if b.err == nil {
+			b.err = fmt.Errorf("%s exceeds %d digits at scale %d", d, decimalPrecision, b.scale)
+		}
+
+		unscaled.SetInt64(0)
+	}
+
+	if unscaled.Sign() < 0 {
+		unscaled.Add(unscaled, decimalModulus)
+	}
+
+	var value [decimalSize]byte
+	unscaled.FillBytes(value[:])
+
+	b.append(parquet.FixedLenByteArrayValue(value[:]), false)
+}
+
+func (b *rowBuilder) time(t time.Time) {
+	if t.IsZero() {
+		b.append(parquet.NullValue(), false)
+		return
+	}
+
+	b.append(parquet.Int64Value(t.UnixNano()), true)
+}
+
+func (b *rowBuilder) int(n int64) {
+	b.append(parquet.Int64Value(n), false)
+}
+
+func (b *rowBuilder) bool(v bool) {
+	b.append(parquet.BooleanValue(v), false)
+}
+
+func (b *rowBuilder) float(f float64, ok bool) {
+	if !ok {
+		b.append(parquet.NullValue(), false)
+		return
+	}
+
+	b.append(parquet.DoubleValue(f), true)
+}
+
+// ParquetBarWriter writes bars to a Parquet file. Prices, volumes and other decimals are stored as Parquet decimals
+// with the scale set by WithDecimalScale, and times as nanosecond UTC timestamps, null for the zero time. The
+// microstructure features of bars without Features are null.
+type ParquetBarWriter struct {
+	writer *parquetWriter
+}
+
+// NewParquetBarWriter creates a ParquetBarWriter that writes to w. The file is incomplete until the writer is closed.
+func NewParquetBarWriter(w io.Writer, options ...Option[ParquetWriterConfig]) (*ParquetBarWriter, error) {
+	writer, err := newParquetWriter(w, "bar", barParquetColumns, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ParquetBarWriter{writer: writer}, nil
+}
+
+// Write writes the bar. Bars are buffered in row groups until Close.
+func (w *ParquetBarWriter) Write(bar Bar) error {
+	return w.writer.write(func(b *rowBuilder) {
+		b.string(bar.Symbol)
+		b.time(bar.Start)
+		b.decimal(bar.Open)
+		b.decimal(bar.High)
+		b.decimal(bar.Low)
+		b.decimal(bar.Close)
+		b.decimal(bar.Volume)
+		b.int(int64(bar.Ticks))
+		b.int(int64(bar.Upticks))
+		b.decimal(bar.BuyVolume)
+		b.decimal(bar.SellVolume)
+		b.decimal(bar.VWAP)
+		b.decimal(bar.Notional)
+		b.time(bar.End)
+		b.time(bar.LastTradeTime)
+		b.int(int64(bar.Downticks))
+		b.decimal(bar.Threshold)
+		b.bool(bar.Provisional)
+		b.int(int64(bar.Revision))
+		b.string(bar.Processor)
+		b.int(bar.Sequence)
+		b.string(string(bar.CloseReason))
+
+		features, ok := Features{}, bar.Features != nil
+		if ok {
+			features = *bar.Features
+		}
+
+		b.float(features.RollSpread, ok)
+		b.float(features.KyleLambda, ok)
+		b.float(features.Amihud, ok)
+		b.float(features.VPIN, ok)
+		b.float(features.OFI, ok)
+		b.float(features.RealizedVariance, ok)
+	})
+}
+
+// Close writes any buffered bars and the file footer. It does not close the underlying writer.
+func (w *ParquetBarWriter) Close() error {
+	return w.writer.writer.Close()
+}
+
+// ParquetTradeWriter writes trades to a Parquet file, in the columns read by ReadTradesParquet, with decimals and
+// times stored as by a ParquetBarWriter.
+type ParquetTradeWriter struct {
+	writer *parquetWriter
+}
+
+// NewParquetTradeWriter creates a ParquetTradeWriter that writes to w. The file is incomplete until the writer is
+// closed.
+func NewParquetTradeWriter(w io.Writer, options ...Option[ParquetWriterConfig]) (*ParquetTradeWriter, error) {
+	writer, err := newParquetWriter(w, "trade", tradeParquetColumns, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ParquetTradeWriter{writer: writer}, nil
+}
+
+// Write writes the trade. Trades are buffered in row groups until Close.
+func (w *ParquetTradeWriter) Write(trade Trade) error {
+	return w.writer.write(func(b *rowBuilder) {
+		b.string(trade.Symbol)
+		b.time(trade.Time)
+		b.decimal(trade.Price)
+		b.decimal(trade.Size)
+		b.string(string(trade.Side))
+		b.string(trade.ID)
+		b.string(string(trade.Action))
+		b.decimal(trade.BuyRatio)
+	})
+}
+
+// Close writes any buffered trades and the file footer. It does not close the underlying writer.
+func (w *ParquetTradeWriter) Close() error {
+	return w.writer.writer.Close()
+}
+
+// ReadBarsParquet reads the bars of a Parquet file of size bytes, such as one written by a ParquetBarWriter. Columns
+// are found by name, and the start, open, high, low, close and volume columns are required.
+func ReadBarsParquet(r io.ReaderAt, size int64) ([]Bar, error) {
+	var bars []Bar
+
+	err := readParquet(r, size, []string{"start", "open", "high", "low", "close", "volume"}, func(d *rowDecoder) {
+		bar := Bar{
+			Symbol:        d.string("symbol"),
+			Start:         d.time("start"),
+			Open:          d.decimal("open"),
+			High:          d.decimal("high"),
+			Low:           d.decimal("low"),
+			Close:         d.decimal("close"),
+			Volume:        d.decimal("volume"),
+			Ticks:         int(d.int("ticks")),
+			Upticks:       int(d.int("upticks")),
+			BuyVolume:     d.decimal("buy_volume"),
+			SellVolume:    d.decimal("sell_volume"),
+			VWAP:          d.decimal("vwap"),
+			Notional:      d.decimal("notional"),
+			End:           d.time("end"),
+			LastTradeTime: d.time("last_trade_time"),
+			Downticks:     int(d.int("downticks")),
+			Threshold:     d.decimal("threshold"),
+			Provisional:   d.bool("provisional"),
+			Revision:      int(d.int("revision")),
+			Processor:     d.string("processor"),
+			Sequence:      d.int("sequence"),
+			CloseReason:   CloseReason(d.string("close_reason")),
+		}
+
+		if rollSpread, ok := d.float("roll_spread"); ok {
+			bar.Features = &Features{RollSpread: rollSpread}
+			bar.Features.KyleLambda, _ = d.float("kyle_lambda")
+			bar.Features.Amihud, _ = d.float("amihud")
+			bar.Features.VPIN, _ = d.float("vpin")
+			bar.Features.OFI, _ = d.float("ofi")
+			bar.Features.RealizedVariance, _ = d.float("realized_variance")
+		}
+
+		bars = append(bars, bar)
+	})
+
+	return bars, err
+}
+
+// ReadTradesParquet reads the trades of a Parquet file of size bytes, for instance to pass to Generate. Columns are
+// found by the JSON field names of Trade, and the price, size and time columns are required. Besides the decimal
+// columns written by a ParquetTradeWriter, prices and sizes may be integers, floats or strings, and times may be
+// timestamps of any unit.
+func ReadTradesParquet(r io.ReaderAt, size int64) ([]Trade, error) {
+	var trades []Trade
+
+	err := readParquet(r, size, []string{"price", "size", "time"}, func(d *rowDecoder) {
+		trade := Trade{
+			Symbol:   d.string("symbol"),
+			Price:    d.decimal("price"),
+			Size:     d.decimal("size"),
+			Time:     d.time("time"),
+			ID:       d.string("id"),
+			Action:   Action(d.string("action")),
+			BuyRatio: d.decimal("buy_ratio"),
+		}
+
+		switch side := Side(d.string("side")); side {
+		case SideBuy, SideSell, "":
+			trade.Side = side
+		default:
+			d.fail("side", fmt.Errorf("unknown side %q", side))
+		}
+
+		trades = append(trades, trade)
+	})
+
+	return trades, err
+}
+
+// readParquet calls decode for each row of the Parquet file, stopping at the first row that cannot be decoded.
+func readParquet(r io.ReaderAt, size int64, required []string, decode func(*rowDecoder)) error {
+	file, err := parquet.OpenFile(r, size)
+	if err != nil {
+		return err
+	}
+
+	d := rowDecoder{columns: make(map[string]parquet.LeafColumn)}
+
+	for _, path := range file.Schema().Columns() {
+		if len(path) == 1 {
+			d.columns[path[0]], _ = file.Schema().Lookup(path...)
+		}
+	}
+
+	for _, name := range required {
+		if _, ok := d.columns[name]; !ok {
+			return fmt.Errorf("missing %s column", name)
+		}
+	}
+
+	rows := make([]parquet.Row, 256)
+
+	for _, group := range file.RowGroups() {
+		if err := d.readGroup(group, rows, decode); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readGroup decodes the rows of a row group.
+func (d *rowDecoder) readGroup(group parquet.RowGroup, buffer []parquet.Row, decode func(*rowDecoder)) error {
+	rows := group.Rows()
+	defer rows.Close()
+
+	for {
+		n, err := rows.ReadRows(buffer)
+
+		for _, row := range buffer[:n] {
+			d.line++
+			d.row = row
+
+			decode(d)
+
+			if d.err != nil {
+				return fmt.Errorf("row %d: %w", d.line, d.err)
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// rowDecoder reads the values of a row by column name, keeping the first error. Missing columns and null values
+// decode to zero values.
+type rowDecoder struct {
+	columns map[string]parquet.LeafColumn
+	row     parquet.Row
+	line    int
+	err     error
+}
+
+// value returns the value of the column and its node, reporting false if the column is missing or null.
+func (d *rowDecoder) value(name string) (parquet.Value, parquet.Node, bool) {
+	column, ok := d.columns[name]
+	if !ok || column.ColumnIndex >= len(d.row) {
+		return parquet.Value{}, nil, false
+	}
+
+	value := d.row[column.ColumnIndex]
+
+	return value, column.Node, !value.IsNull()
+}
+
+// fail records the first error.
+func (d *rowDecoder) fail(name string, err error) {
+	if d.err == nil {
+		d.err = fmt.Errorf("%s column: %w", name, err)
+	}
+}
+
+func (d *rowDecoder) string(name string) string {
+	value, _, ok := d.value(name)
+	if !ok {
+		return ""
+	}
+
+	return string(value.ByteArray())
+}
+
+func (d *rowDecoder) int(name string) int64 {
+	value, _, ok := d.value(name)
+	if !ok {
+		return 0
+	}
+
+	return value.Int64()
+}
+
+func (d *rowDecoder) bool(name string) bool {
+	value, _, ok := d.value(name)
+
+	return ok && value.Boolean()
+}
+
+func (d *rowDecoder) float(name string) (float64, bool) {
+	value, _, ok := d.value(name)
+	if !ok {
+		return 0, false
+	}
+
+	return value.Double(), true
+}
+
+func (d *rowDecoder) decimal(name string) decimal.Decimal {
+	value, node, ok := d.value(name)
+	if !ok {
+		return decimal.Zero
+	}
+
+	if logical := node.Type().LogicalType(); logical != nil && logical.Decimal != nil {
+		exp := -logical.Decimal.Scale
+
+		switch value.Kind() {
+		case parquet.Int32:
+			return decimal.New(int64(value.Int32()), exp)
+		case parquet.Int64:
+			return decimal.New(value.Int64(), exp)
+		case parquet.FixedLenByteArray, parquet.ByteArray:
+			unscaled := new(big.Int).SetBytes(value.ByteArray())
+			if b := value.ByteArray(); len(b) > 0 && b[0]&0x80 != 0 {
+				unscaled.Sub(unscaled, new(big.Int).Lsh(big.NewInt(1), uint(8*len(b))))
+			}
+
+			return decimal.NewFromBigInt(unscaled, exp)
+		}
+	}
+
+	switch value.Kind() {
+	case parquet.Int32:
+		return decimal.NewFromInt(int64(value.Int32()))
+	case parquet.Int64:
+		return decimal.NewFromInt(value.Int64())
+	case parquet.Float, parquet.Double:
+		return decimal.NewFromFloat(value.Double())
+	case parquet.ByteArray:
+		parsed, err := decimal.NewFromString(string(value.ByteArray()))
+		if err != nil {
+			d.fail(name, err)
+		}
+
+		return parsed
+	}
+
+	d.fail(name, fmt.Errorf("unsupported %s decimal", value.Kind()))
+
+	return decimal.Zero
+}
+
+func (d *rowDecoder) time(name string) time.Time {
+	value, node, ok := d.value(name)
+	if !ok {
+		return time.Time{}
+	}
+
+	if value.Kind() != parquet.Int64 {
+		d.fail(name, fmt.Errorf("unsupported %s timestamp", value.Kind()))
+		return time.Time{}
+	}
+
+	n := value.Int64()
+
+	// timestamps without a unit are taken to be nanoseconds
+	if logical := node.Type().LogicalType(); logical != nil && logical.Timestamp != nil {
+		switch unit := logical.Timestamp.Unit; {
+		case unit.Millis != nil:
+			return time.UnixMilli(n).UTC()
+		case unit.Micros != nil:
+			return time.UnixMicro(n).UTC()
+		}
+	}
+
+	return time.Unix(0, n).UTC()
+}
@@ -0,0 +1,319 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender_test
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+	"time"
+
+	decimal "github.com/alpacahq/alpacadecimal"
+	"github.com/csgriffis/bartender"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/parquet-go/parquet-go"
+)
+
+func TestParquetBarWriter(t *testing.T) {
+	start := time.Date(2025, 1, 2, 14, 30, 0, 123456789, time.UTC)
+
+	bars := []bartender.Bar{
+		{
+			Symbol:        "BTC/USD",
+			Open:          decimal.RequireFromString("64123.12345678"),
+			High:          decimal.RequireFromString("64200.5"),
+			Low:           decimal.RequireFromString("64000"),
+			Close:         decimal.RequireFromString("64100.00000001"),
+			Volume:        decimal.RequireFromString("1.23456789"),
+			Start:         start,
+			End:           start.Add(time.Minute),
+			BuyVolume:     decimal.RequireFromString("1"),
+			SellVolume:    decimal.RequireFromString("0.23456789"),
+			Ticks:         12,
+			Upticks:       5,
+			Downticks:     4,
+			VWAP:          decimal.RequireFromString("64111.11111111"),
+			Notional:      decimal.RequireFromString("79149.16240000"),
+			LastTradeTime: start.Add(59 * time.Second),
+			Threshold:     decimal.RequireFromString("-12.5"),
+			Revision:      1,
+			Processor:     "TimeBarConfig",
+			Sequence:      7,
+			CloseReason:   bartender.CloseReasonInterval,
+			Features:      &bartender.Features{RollSpread: 0.5, KyleLambda: 1e-6, VPIN: 0.25, OFI: -3, RealizedVariance: 1e-4},
+		},
+		{
+			// an empty bar filling a gap, without trades or features
+			Symbol:      "BTC/USD",
+			Open:        decimal.RequireFromString("64100.00000001"),
+			High:        decimal.RequireFromString("64100.00000001"),
+			Low:         decimal.RequireFromString("64100.00000001"),
+			Close:       decimal.RequireFromString("64100.00000001"),
+			Start:       start.Add(time.Minute),
+			End:         start.Add(2 * time.Minute),
+			Provisional: true,
+		},
+	}
+
+	var buf bytes.Buffer
+
+	w, err := bartender.NewParquetBarWriter(&buf)
+	if err != nil {
+		t.Fatalf("NewParquetBarWriter() error = %v", err)
+	}
+
+	for _, bar := range bars {
+		if err := w.Write(bar); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	got, err := bartender.ReadBarsParquet(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("ReadBarsParquet() error = %v", err)
+	}
+
+	if diff := cmp.Diff(bars, got, cmpopts.IgnoreUnexported(bartender.Bar{}), equateDecimals); diff != "" {
+		t.Errorf("ReadBarsParquet() (-want +got):\n%s", diff)
+	}
+
+	// columns keep the order of the CSV columns, with decimals of the default scale and nanosecond timestamps
+	file, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+
+	var names []string
+	for _, path := range file.Schema().Columns() {
+		names = append(names, path[0])
+	}
+
+	if !slices.Equal(names[:6], []string{"symbol", "start", "open", "high", "low", "close"}) {
+		t.Errorf("columns = %v", names)
+	}
+
+	open, _ := file.Schema().Lookup("open")
+	if got := open.Node.Type().LogicalType().Decimal; got == nil || got.Scale != 8 || got.Precision != 38 {
+		t.Errorf("open column type = %v, want DECIMAL(38,8)", open.Node.Type())
+	}
+
+	startColumn, _ := file.Schema().Lookup("start")
+	if got := startColumn.Node.Type().LogicalType().Timestamp; got == nil || got.Unit.Nanos == nil {
+		t.Errorf("start column type = %v, want nanosecond timestamp", startColumn.Node.Type())
+	}
+}
+
+func TestParquetTradeWriter(t *testing.T) {
+	at := time.Date(2025, 1, 2, 14, 30, 0, 1, time.UTC)
+
+	trades := []bartender.Trade{
+		{Symbol: "AAPL", Price: decimal.RequireFromString("187.4449"), Size: decimal.NewFromInt(100), Side: bartender.SideBuy, Time: at, ID: "1"},
+		{Symbol: "AAPL", Price: decimal.RequireFromString("187.45"), Size: decimal.NewFromInt(-5), Time: at.Add(time.Millisecond), ID: "1", Action: bartender.ActionCorrect, BuyRatio: decimal.RequireFromString("0.25")},
+	}
+
+	tt := []struct {
+		name    string
+		options []bartender.Option[bartender.ParquetWriterConfig]
+		price   string
+		wantErr bool
+	}{
+		{name: "Default Scale", price: "187.4449"},
+		{name: "Rounded", options: []bartender.Option[bartender.ParquetWriterConfig]{bartender.WithDecimalScale(2)}, price: "187.44"},
+		{name: "Invalid Scale", options: []bartender.Option[bartender.ParquetWriterConfig]{bartender.WithDecimalScale(39)}, wantErr: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			w, err := bartender.NewParquetTradeWriter(&buf, tc.options...)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("NewParquetTradeWriter() error = %v, wantErr %v", err, tc.wantErr)
+			}
+
+			if tc.wantErr {
+				return
+			}
+
+			for _, trade := range trades {
+				if err := w.Write(trade); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+			}
+
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			got, err := bartender.ReadTradesParquet(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+			if err != nil {
+				t.Fatalf("ReadTradesParquet() error = %v", err)
+			}
+
+			want := slices.Clone(trades)
+			want[0].Price = decimal.RequireFromString(tc.price)
+
+			if diff := cmp.Diff(want, got, equateDecimals); diff != "" {
+				t.Errorf("ReadTradesParquet() (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParquetTradeWriter_Overflow(t *testing.T) {
+	w, err := bartender.NewParquetTradeWriter(&bytes.Buffer{}, bartender.WithDecimalScale(30))
+	if err != nil {
+		t.Fatalf("NewParquetTradeWriter() error = %v", err)
+	}
+
+	if err := w.Write(bartender.Trade{Price: decimal.NewFromInt(1e9)}); err == nil {
+		t.Error("Write() error = nil, want error for a price exceeding 38 digits")
+	}
+}
+
+func TestReadTradesParquet(t *testing.T) {
+	// trades as written by other tools, with float prices and millisecond timestamps
+	type row struct {
+		Time   int64   `parquet:"time,timestamp(millisecond)"`
+		Price  float64 `parquet:"price"`
+		Size   int64   `parquet:"size"`
+		Side   string  `parquet:"side"`
+		Ticker string  `parquet:"ticker"`
+	}
+
+	tt := []struct {
+		name    string
+		rows    []row
+		want    []bartender.Trade
+		wantErr bool
+	}{
+		{
+			name: "Foreign Types",
+			rows: []row{{Time: 1735828200000, Price: 100.25, Size: 10, Side: "sell"}},
+			want: []bartender.Trade{
+				{Price: decimal.RequireFromString("100.25"), Size: decimal.NewFromInt(10), Side: bartender.SideSell, Time: time.Date(2025, 1, 2, 14, 30, 0, 0, time.UTC)},
+			},
+		},
+		{name: "Unknown Side", rows: []row{{Time: 1, Price: 1, Size: 1, Side: "short"}}, wantErr: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := parquet.Write(&buf, tc.rows); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+
+			got, err := bartender.ReadTradesParquet(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ReadTradesParquet() error = %v, wantErr %v", err, tc.wantErr)
+			}
+
+			if tc.wantErr {
+				return
+			}
+
+			if diff := cmp.Diff(tc.want, got, equateDecimals); diff != "" {
+				t.Errorf("ReadTradesParquet() (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReadTradesParquet_MissingColumn(t *testing.T) {
+	type row struct {
+		Price float64 `parquet:"price"`
+		Size  float64 `parquet:"size"`
+	}
+
+	var buf bytes.Buffer
+	if err := parquet.Write(&buf, []row{{Price: 1, Size: 1}}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	if _, err := bartender.ReadTradesParquet(bytes.NewReader(buf.Bytes()), int64(buf.Len())); err == nil {
+		t.Error("ReadTradesParquet() error = nil, want error for a missing time column")
+	}
+}
+
+func TestParquet_Generate(t *testing.T) {
+	at := time.Date(2025, 1, 2, 14, 30, 0, 0, time.UTC)
+
+	var trades bytes.Buffer
+
+	tw, err := bartender.NewParquetTradeWriter(&trades)
+	if err != nil {
+		t.Fatalf("NewParquetTradeWriter() error = %v", err)
+	}
+
+	for i := range 10 {
+		trade := bartender.Trade{Symbol: "AAPL", Price: decimal.NewFromInt(100 + int64(i%3)), Size: decimal.NewFromInt(1), Time: at.Add(time.Duration(i) * time.Second)}
+		if err := tw.Write(trade); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	source, err := bartender.ReadTradesParquet(bytes.NewReader(trades.Bytes()), int64(trades.Len()))
+	if err != nil {
+		t.Fatalf("ReadTradesParquet() error = %v", err)
+	}
+
+	p, err := bartender.New(bartender.WithTickThreshold(5))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	want, err := bartender.Generate(source, p)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	var bars bytes.Buffer
+
+	bw, err := bartender.NewParquetBarWriter(&bars)
+	if err != nil {
+		t.Fatalf("NewParquetBarWriter() error = %v", err)
+	}
+
+	for _, bar := range want {
+		if err := bw.Write(bar); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+
+	if err := bw.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	got, err := bartender.ReadBarsParquet(bytes.NewReader(bars.Bytes()), int64(bars.Len()))
+	if err != nil {
+		t.Fatalf("ReadBarsParquet() error = %v", err)
+	}
+
+	if len(want) != 2 {
+		t.Fatalf("Generate() = %d bars, want 2", len(want))
+	}
+
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreUnexported(bartender.Bar{}), equateDecimals); diff != "" {
+		t.Errorf("ReadBarsParquet() (-want +got):\n%s", diff)
+	}
+}
+
+// equateDecimals compares decimals by value, as decimals read from Parquet carry the scale of their column.
+var equateDecimals = cmp.Comparer(func(a, b decimal.Decimal) bool {
+	return a.Equal(b)
+})
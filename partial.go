@@ -0,0 +1,65 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender
+
+import (
+	"context"
+	"time"
+)
+
+// WithPartialBars emits provisional snapshots of the bar in progress on the processor's output, alongside the bars it
+// closes. A snapshot is emitted for the first trade of every bar and then at most once per throttle of trade time, so
+// a throttle of zero emits a snapshot after every trade. Snapshots have Provisional set and are superseded by later
+// snapshots and by the closed bar with the same Start. The processor type must be given explicitly, e.g.
+// WithPartialBars[TimeBarConfig](time.Second).
+func WithPartialBars[T any, PT configurable[T]](throttle time.Duration) Option[T] {
+	return func(c *T) {
+		PT(c).setPartialBars(throttle)
+	}
+}
+
+func (c *processorConfig) setPartialBars(throttle time.Duration) {
+	c.partialBars = true
+	c.partialThrottle = throttle
+}
+
+// partials returns a publisher for the provisional snapshots of a single run of the processor.
+func (c processorConfig) partials() *partialPublisher {
+	return &partialPublisher{enabled: c.partialBars, throttle: c.partialThrottle, processor: c.name}
+}
+
+// partialPublisher emits throttled snapshots of the bar in progress.
+type partialPublisher struct {
+	enabled   bool
+	throttle  time.Duration
+	processor string
+
+	// bar is the bar in progress when the last snapshot was emitted, at the time of last
+	bar  *Bar
+	last time.Time
+}
+
+// publish sends a provisional snapshot of current after the trade is applied, unless the snapshot is throttled. It
+// returns false if the context was cancelled before the snapshot was sent.
+func (p *partialPublisher) publish(ctx context.Context, output chan<- *Bar, trade Trade, current *Bar) bool {
+	if !p.enabled || current == nil {
+		return true
+	}
+
+	if current == p.bar && trade.Time.Sub(p.last) < p.throttle {
+		return true
+	}
+
+	p.bar, p.last = current, trade.Time
+
+	snapshot := *current
+	snapshot.Provisional = true
+	snapshot.Processor = p.processor
+
+	return send(ctx, output, &snapshot)
+}
@@ -0,0 +1,94 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender_test
+
+import (
+	"testing"
+	"time"
+
+	decimal "github.com/alpacahq/alpacadecimal"
+	"github.com/csgriffis/bartender"
+)
+
+func TestWithPartialBars(t *testing.T) {
+	trades := []bartender.Trade{
+		{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{Price: decimal.NewFromInt(101), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 10, 0, time.UTC)},
+		{Price: decimal.NewFromInt(99), Size: decimal.NewFromInt(1), Side: bartender.SideSell, Time: time.Date(2025, 1, 1, 10, 0, 20, 0, time.UTC)},
+		{Price: decimal.NewFromInt(102), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 30, 0, time.UTC)},
+		{Price: decimal.NewFromInt(103), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 1, 5, 0, time.UTC)},
+	}
+
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	tt := []TestCase[time.Duration]{
+		{
+			name:   "Every Trade",
+			input:  0,
+			trades: trades,
+			want: []bartender.Bar{
+				{Open: decimal.NewFromInt(100), High: decimal.NewFromInt(100), Low: decimal.NewFromInt(100), Close: decimal.NewFromInt(100), Volume: decimal.NewFromInt(1), Start: start, BuyVolume: decimal.NewFromInt(1), Ticks: 1, Provisional: true},
+				{Open: decimal.NewFromInt(100), High: decimal.NewFromInt(101), Low: decimal.NewFromInt(100), Close: decimal.NewFromInt(101), Volume: decimal.NewFromInt(2), Start: start, BuyVolume: decimal.NewFromInt(2), Ticks: 2, Upticks: 1, Provisional: true},
+				{Open: decimal.NewFromInt(100), High: decimal.NewFromInt(101), Low: decimal.NewFromInt(99), Close: decimal.NewFromInt(99), Volume: decimal.NewFromInt(3), Start: start, BuyVolume: decimal.NewFromInt(2), SellVolume: decimal.NewFromInt(1), Ticks: 3, Upticks: 1, Provisional: true},
+				{Open: decimal.NewFromInt(100), High: decimal.NewFromInt(102), Low: decimal.NewFromInt(99), Close: decimal.NewFromInt(102), Volume: decimal.NewFromInt(4), Start: start, BuyVolume: decimal.NewFromInt(3), SellVolume: decimal.NewFromInt(1), Ticks: 4, Upticks: 2, Provisional: true},
+				{Open: decimal.NewFromInt(100), High: decimal.NewFromInt(102), Low: decimal.NewFromInt(99), Close: decimal.NewFromInt(102), Volume: decimal.NewFromInt(4), Start: start, BuyVolume: decimal.NewFromInt(3), SellVolume: decimal.NewFromInt(1), Ticks: 4, Upticks: 2},
+				{Open: decimal.NewFromInt(103), High: decimal.NewFromInt(103), Low: decimal.NewFromInt(103), Close: decimal.NewFromInt(103), Volume: decimal.NewFromInt(1), Start: start.Add(time.Minute), BuyVolume: decimal.NewFromInt(1), Ticks: 1, Provisional: true},
+				{Open: decimal.NewFromInt(103), High: decimal.NewFromInt(103), Low: decimal.NewFromInt(103), Close: decimal.NewFromInt(103), Volume: decimal.NewFromInt(1), Start: start.Add(time.Minute), BuyVolume: decimal.NewFromInt(1), Ticks: 1},
+			},
+		},
+		{
+			name:   "Throttled",
+			input:  20 * time.Second,
+			trades: trades,
+			want: []bartender.Bar{
+				{Open: decimal.NewFromInt(100), High: decimal.NewFromInt(100), Low: decimal.NewFromInt(100), Close: decimal.NewFromInt(100), Volume: decimal.NewFromInt(1), Start: start, BuyVolume: decimal.NewFromInt(1), Ticks: 1, Provisional: true},
+				{Open: decimal.NewFromInt(100), High: decimal.NewFromInt(101), Low: decimal.NewFromInt(99), Close: decimal.NewFromInt(99), Volume: decimal.NewFromInt(3), Start: start, BuyVolume: decimal.NewFromInt(2), SellVolume: decimal.NewFromInt(1), Ticks: 3, Upticks: 1, Provisional: true},
+				{Open: decimal.NewFromInt(100), High: decimal.NewFromInt(102), Low: decimal.NewFromInt(99), Close: decimal.NewFromInt(102), Volume: decimal.NewFromInt(4), Start: start, BuyVolume: decimal.NewFromInt(3), SellVolume: decimal.NewFromInt(1), Ticks: 4, Upticks: 2},
+				{Open: decimal.NewFromInt(103), High: decimal.NewFromInt(103), Low: decimal.NewFromInt(103), Close: decimal.NewFromInt(103), Volume: decimal.NewFromInt(1), Start: start.Add(time.Minute), BuyVolume: decimal.NewFromInt(1), Ticks: 1, Provisional: true},
+				{Open: decimal.NewFromInt(103), High: decimal.NewFromInt(103), Low: decimal.NewFromInt(103), Close: decimal.NewFromInt(103), Volume: decimal.NewFromInt(1), Start: start.Add(time.Minute), BuyVolume: decimal.NewFromInt(1), Ticks: 1},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		p, err := bartender.New(
+			bartender.WithInterval(time.Minute),
+			bartender.WithPartialBars[bartender.TimeBarConfig](tc.input),
+		)
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+
+		tc.Run(t, p)
+	}
+}
+
+func TestWithPartialBars_ClosedBar(t *testing.T) {
+	trades := []bartender.Trade{
+		{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{Price: decimal.NewFromInt(101), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 10, 0, time.UTC)},
+	}
+
+	p, err := bartender.New(
+		bartender.WithTickThreshold(2),
+		bartender.WithPartialBars[bartender.TickBarConfig](0),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	bars, err := bartender.Generate(trades, p)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	// the trade that closes a bar emits the closed bar only
+	if len(bars) != 2 || !bars[0].Provisional || bars[1].Provisional || bars[1].Ticks != 2 {
+		t.Errorf("Generate() = %+v, want a provisional bar of 1 tick and a closed bar of 2 ticks", bars)
+	}
+}
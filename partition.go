@@ -0,0 +1,90 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Partition returns a Processor that routes each trade by Symbol to a processor of its own, created by the factory
+// when the first trade of the symbol arrives. The processors run concurrently and their bars are merged into one
+// output: the bars of a symbol keep the order its processor emitted them in, while the bars of different symbols may
+// interleave.
+//
+// If the factory returns an error, Generate and GenerateStreamContext stop the stream and return it. Processors run
+// outside of them drop the trades of that symbol instead.
+func Partition(factory func(symbol string) (Processor, error)) Processor {
+	return partitionProcessor{factory: factory}
+}
+
+type partitionProcessor struct {
+	factory func(symbol string) (Processor, error)
+}
+
+func (p partitionProcessor) Process(trades <-chan Trade) chan *Bar {
+	return p.ProcessContext(context.Background(), trades)
+}
+
+func (p partitionProcessor) ProcessContext(ctx context.Context, trades <-chan Trade) chan *Bar {
+	output := make(chan *Bar)
+
+	go func() {
+		defer close(output)
+
+		var wg sync.WaitGroup
+
+		// input of the processor for each symbol, nil if it could not be created
+		inputs := make(map[string]chan Trade)
+
+		for trade := range receive(ctx, trades) {
+			input, ok := inputs[trade.Symbol]
+			if !ok {
+				processor, err := p.factory(trade.Symbol)
+				if err != nil {
+					abort(ctx, fmt.Errorf("partition %q: %w", trade.Symbol, err))
+				} else {
+					input = make(chan Trade)
+
+					wg.Add(1)
+					go func(bars chan *Bar) {
+						defer wg.Done()
+
+						for bar := range bars {
+							if !send(ctx, output, bar) {
+								return
+							}
+						}
+					}(process(ctx, processor, input))
+				}
+
+				inputs[trade.Symbol] = input
+			}
+
+			if input != nil && !send(ctx, input, trade) {
+				break
+			}
+		}
+
+		// let every processor flush its bar in progress
+		for _, input := range inputs {
+			if input != nil {
+				close(input)
+			}
+		}
+
+		wg.Wait()
+	}()
+
+	return output
+}
+
+// Interface guards
+var _ Processor = (*partitionProcessor)(nil)
+var _ ContextProcessor = (*partitionProcessor)(nil)
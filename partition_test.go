@@ -0,0 +1,138 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	decimal "github.com/alpacahq/alpacadecimal"
+	"github.com/csgriffis/bartender"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestPartition(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	trades := []bartender.Trade{
+		{Symbol: "AAPL", Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: start},
+		{Symbol: "MSFT", Price: decimal.NewFromInt(400), Size: decimal.NewFromInt(2), Side: bartender.SideSell, Time: start.Add(time.Second)},
+		{Symbol: "AAPL", Price: decimal.NewFromInt(101), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: start.Add(2 * time.Second)},
+		{Symbol: "AAPL", Price: decimal.NewFromInt(102), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: start.Add(3 * time.Second)},
+		{Symbol: "MSFT", Price: decimal.NewFromInt(399), Size: decimal.NewFromInt(2), Side: bartender.SideSell, Time: start.Add(4 * time.Second)},
+	}
+
+	want := map[string][]bartender.Bar{
+		"AAPL": {
+			{
+				Symbol:    "AAPL",
+				Open:      decimal.NewFromInt(100),
+				High:      decimal.NewFromInt(101),
+				Low:       decimal.NewFromInt(100),
+				Close:     decimal.NewFromInt(101),
+				Volume:    decimal.NewFromInt(2),
+				Start:     start,
+				BuyVolume: decimal.NewFromInt(2),
+				Ticks:     2,
+				Upticks:   1,
+			},
+			{
+				Symbol:    "AAPL",
+				Open:      decimal.NewFromInt(102),
+				High:      decimal.NewFromInt(102),
+				Low:       decimal.NewFromInt(102),
+				Close:     decimal.NewFromInt(102),
+				Volume:    decimal.NewFromInt(1),
+				Start:     start.Add(3 * time.Second),
+				BuyVolume: decimal.NewFromInt(1),
+				Ticks:     1,
+			},
+		},
+		"MSFT": {
+			{
+				Symbol:     "MSFT",
+				Open:       decimal.NewFromInt(400),
+				High:       decimal.NewFromInt(400),
+				Low:        decimal.NewFromInt(399),
+				Close:      decimal.NewFromInt(399),
+				Volume:     decimal.NewFromInt(4),
+				Start:      start.Add(time.Second),
+				SellVolume: decimal.NewFromInt(4),
+				Ticks:      2,
+			},
+		},
+	}
+
+	var created []string
+
+	p := bartender.Partition(func(symbol string) (bartender.Processor, error) {
+		created = append(created, symbol)
+
+		return bartender.New(bartender.WithTickThreshold(2))
+	})
+
+	bars, err := bartender.Generate(trades, p)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	got := make(map[string][]bartender.Bar)
+	for _, bar := range bars {
+		got[bar.Symbol] = append(got[bar.Symbol], bar)
+	}
+
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreUnexported(bartender.Bar{}), ignoreBarStats); diff != "" {
+		t.Errorf("Generate() mismatch (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff([]string{"AAPL", "MSFT"}, created); diff != "" {
+		t.Errorf("factory calls mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPartition_FactoryError(t *testing.T) {
+	errUnknown := errors.New("unknown symbol")
+
+	p := bartender.Partition(func(symbol string) (bartender.Processor, error) {
+		if symbol != "AAPL" {
+			return nil, errUnknown
+		}
+
+		return bartender.New(bartender.WithTickThreshold(1))
+	})
+
+	trades := []bartender.Trade{
+		{Symbol: "AAPL", Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Time: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{Symbol: "XXXX", Price: decimal.NewFromInt(1), Size: decimal.NewFromInt(1), Time: time.Date(2025, 1, 1, 10, 0, 1, 0, time.UTC)},
+	}
+
+	if _, err := bartender.Generate(trades, p); !errors.Is(err, errUnknown) {
+		t.Errorf("Generate() error = %v, want %v", err, errUnknown)
+	}
+
+	tradesCh := make(chan bartender.Trade, len(trades))
+	for _, trade := range trades {
+		tradesCh <- trade
+	}
+	close(tradesCh)
+
+	bars, errs, err := bartender.GenerateStreamContext(context.Background(), tradesCh, p)
+	if err != nil {
+		t.Fatalf("GenerateStreamContext() error = %v", err)
+	}
+
+	for range bars {
+	}
+
+	if err := <-errs; !errors.Is(err, errUnknown) {
+		t.Errorf("GenerateStreamContext() error = %v, want %v", err, errUnknown)
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender
+
+import (
+	decimal "github.com/alpacahq/alpacadecimal"
+)
+
+// Precision holds the number of decimal places the prices of each symbol are written with, such as 2 for US equities
+// and 8 for crypto pairs. The entry for the empty symbol applies to every symbol without an entry of its own. Prices
+// of symbols without either are written exactly.
+type Precision map[string]int32
+
+// Places returns the number of decimal places of the symbol's prices, reporting false if they are written exactly.
+func (p Precision) Places(symbol string) (int32, bool) {
+	if places, ok := p[symbol]; ok {
+		return places, true
+	}
+
+	places, ok := p[""]
+
+	return places, ok
+}
+
+// Format formats a price of the symbol, rounded and padded to its number of decimal places.
+func (p Precision) Format(symbol string, price decimal.Decimal) string {
+	if places, ok := p.Places(symbol); ok {
+		return price.StringFixed(places)
+	}
+
+	return price.String()
+}
+
+// Round returns a copy of the bar with its prices, Open, High, Low, Close and VWAP, rounded to the number of decimal
+// places of its symbol. Use it ahead of output formats without precision options of their own, such as JSON.
+func (p Precision) Round(bar Bar) Bar {
+	places, ok := p.Places(bar.Symbol)
+	if !ok {
+		return bar
+	}
+
+	bar.Open = bar.Open.Round(places)
+	bar.High = bar.High.Round(places)
+	bar.Low = bar.Low.Round(places)
+	bar.Close = bar.Close.Round(places)
+	bar.VWAP = bar.VWAP.Round(places)
+
+	return bar
+}
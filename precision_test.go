@@ -0,0 +1,79 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	decimal "github.com/alpacahq/alpacadecimal"
+	"github.com/csgriffis/bartender"
+)
+
+func TestPrecision_Format(t *testing.T) {
+	precision := bartender.Precision{"": 2, "BTC/USD": 8, "EUR/USD": 5}
+
+	tt := []struct {
+		name      string
+		precision bartender.Precision
+		symbol    string
+		price     string
+		want      string
+	}{
+		{"Symbol Precision", precision, "BTC/USD", "64123.123456789", "64123.12345679"},
+		{"Padded", precision, "EUR/USD", "1.08", "1.08000"},
+		{"Default Precision", precision, "AAPL", "187.4449", "187.44"},
+		{"Exact Without Default", bartender.Precision{"BTC/USD": 8}, "PENNY", "0.000123", "0.000123"},
+		{"Exact Without Precision", nil, "BTC/USD", "64123.123456789", "64123.123456789"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.precision.Format(tc.symbol, decimal.RequireFromString(tc.price)); got != tc.want {
+				t.Errorf("Format() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPrecision_Round(t *testing.T) {
+	bar := bartender.Bar{
+		Symbol: "EUR/USD",
+		Open:   decimal.RequireFromString("1.083456"),
+		High:   decimal.RequireFromString("1.084"),
+		Low:    decimal.RequireFromString("1.082119"),
+		Close:  decimal.RequireFromString("1.0831"),
+		Volume: decimal.RequireFromString("1000000.123456"),
+		VWAP:   decimal.RequireFromString("1.0832449"),
+	}
+
+	data, err := json.Marshal(bartender.Precision{"EUR/USD": 4}.Round(bar))
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var got struct {
+		Open, High, Low, Close, Volume, VWAP string
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	// prices are rounded while the volume is kept exact
+	want := []string{"1.0835", "1.084", "1.0821", "1.0831", "1000000.123456", "1.0832"}
+	for i, field := range []string{got.Open, got.High, got.Low, got.Close, got.Volume, got.VWAP} {
+		if field != want[i] {
+			t.Errorf("Round() = %s, want prices %v", data, want)
+			break
+		}
+	}
+
+	if !bar.Open.Equal(decimal.RequireFromString("1.083456")) {
+		t.Errorf("Round() modified the bar")
+	}
+}
@@ -0,0 +1,219 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	decimal "github.com/alpacahq/alpacadecimal"
+)
+
+// Quote is a top of book (NBBO) update.
+type Quote struct {
+	Symbol   string          `json:"symbol"`
+	BidPrice decimal.Decimal `json:"bid_price"`
+	BidSize  decimal.Decimal `json:"bid_size"`
+	AskPrice decimal.Decimal `json:"ask_price"`
+	AskSize  decimal.Decimal `json:"ask_size"`
+	Time     time.Time       `json:"time"`
+}
+
+// Mid returns the midpoint between the bid and ask prices.
+func (q Quote) Mid() decimal.Decimal {
+	return q.BidPrice.Add(q.AskPrice).Div(decimal.NewFromInt(2))
+}
+
+// Spread returns the difference between the ask and bid prices.
+func (q Quote) Spread() decimal.Decimal {
+	return q.AskPrice.Sub(q.BidPrice)
+}
+
+// OHLC is the open, high, low and close of a price series.
+type OHLC struct {
+	Open  decimal.Decimal `json:"open"`
+	High  decimal.Decimal `json:"high"`
+	Low   decimal.Decimal `json:"low"`
+	Close decimal.Decimal `json:"close"`
+}
+
+func newOHLC(price decimal.Decimal) OHLC {
+	return OHLC{Open: price, High: price, Low: price, Close: price}
+}
+
+func (o *OHLC) apply(price decimal.Decimal) {
+	o.High = decimal.Max(o.High, price)
+	o.Low = decimal.Min(o.Low, price)
+	o.Close = price
+}
+
+// QuoteBar aggregates the quotes of an interval.
+type QuoteBar struct {
+	Symbol string    `json:"symbol"`
+	Start  time.Time `json:"start"`
+	Bid    OHLC      `json:"bid"`
+	Ask    OHLC      `json:"ask"`
+	Mid    OHLC      `json:"mid"`
+
+	// Spread is the time-weighted average spread over the bar.
+	Spread decimal.Decimal `json:"spread"`
+	Quotes int             `json:"quotes"`
+
+	prevailing     *Quote
+	prevailingFrom time.Time
+	weightedSpread decimal.Decimal
+	covered        time.Duration
+}
+
+// newQuoteBar starts a bar at start. When a prevailing quote is carried over from the previous bar it opens the bar.
+func newQuoteBar(start time.Time, prevailing *Quote) *QuoteBar {
+	b := &QuoteBar{Start: start}
+
+	if prevailing != nil {
+		b.Symbol = prevailing.Symbol
+		b.Bid = newOHLC(prevailing.BidPrice)
+		b.Ask = newOHLC(prevailing.AskPrice)
+		b.Mid = newOHLC(prevailing.Mid())
+		b.prevailing = prevailing
+		b.prevailingFrom = start
+	}
+
+	return b
+}
+
+func (b *QuoteBar) applyQuote(q Quote) {
+	if b.Symbol == "" {
+		b.Symbol = q.Symbol
+	}
+
+	if b.prevailing == nil {
+		b.Bid = newOHLC(q.BidPrice)
+		b.Ask = newOHLC(q.AskPrice)
+		b.Mid = newOHLC(q.Mid())
+	} else {
+		b.weigh(q.Time)
+		b.Bid.apply(q.BidPrice)
+		b.Ask.apply(q.AskPrice)
+		b.Mid.apply(q.Mid())
+	}
+
+	b.Quotes++
+	b.prevailing = &q
+	b.prevailingFrom = q.Time
+}
+
+// weigh accumulates the spread of the prevailing quote up to t.
+func (b *QuoteBar) weigh(t time.Time) {
+	elapsed := t.Sub(b.prevailingFrom)
+	if elapsed <= 0 {
+		return
+	}
+
+	b.weightedSpread = b.weightedSpread.Add(b.prevailing.Spread().Mul(decimal.NewFromInt(elapsed.Nanoseconds())))
+	b.covered += elapsed
+}
+
+// finalize completes the time-weighted spread at the end of the bar.
+func (b *QuoteBar) finalize(end time.Time) {
+	if b.prevailing == nil {
+		return
+	}
+
+	b.weigh(end)
+
+	if b.covered > 0 {
+		b.Spread = b.weightedSpread.Div(decimal.NewFromInt(b.covered.Nanoseconds()))
+	} else {
+		b.Spread = b.prevailing.Spread()
+	}
+}
+
+// QuoteProcessor is the handler passed to the quote Generate functions.
+type QuoteProcessor interface {
+	// ProcessQuotes aggregates quotes into bars. It owns the returned QuoteBar channel and closes it once the Quote
+	// channel has been closed and all remaining quotes have been processed, or the context is cancelled.
+	ProcessQuotes(context.Context, <-chan Quote) chan *QuoteBar
+}
+
+func WithQuoteInterval(interval time.Duration) Option[QuoteBarConfig] {
+	return func(q *QuoteBarConfig) {
+		q.interval = interval
+	}
+}
+
+// WithQuoteAlignment anchors quote bar intervals to a time of day in the given location, see WithAlignment.
+func WithQuoteAlignment(location *time.Location, offset time.Duration) Option[QuoteBarConfig] {
+	return func(q *QuoteBarConfig) {
+		q.alignment = alignment{location: location, offset: offset}
+	}
+}
+
+// QuoteBarConfig aggregates quotes into time bars aligned the same way as TimeBarConfig. Intervals without quotes
+// produce bars carrying the prevailing quote.
+type QuoteBarConfig struct {
+	interval  time.Duration `validate:"required"`
+	alignment alignment
+}
+
+func (c QuoteBarConfig) ProcessQuotes(ctx context.Context, quotes <-chan Quote) chan *QuoteBar {
+	output := make(chan *QuoteBar)
+
+	go func() {
+		defer close(output)
+
+		var current *QuoteBar
+		for quote := range receive(ctx, quotes) {
+			alignedStart := c.alignment.start(quote.Time, c.interval)
+
+			// is the quote before the current interval?
+			if current != nil && quote.Time.Before(current.Start) {
+				// then drop the quote
+				continue
+			}
+
+			// finalize the current interval and any empty intervals before the quote
+			for current != nil && current.Start.Before(alignedStart) {
+				end := c.alignment.end(current.Start, c.interval)
+				current.finalize(end)
+
+				if !send(ctx, output, current) {
+					return
+				}
+
+				current = newQuoteBar(end, current.prevailing)
+			}
+
+			if current == nil {
+				current = newQuoteBar(alignedStart, nil)
+			}
+
+			current.applyQuote(quote)
+		}
+
+		// send the last bar
+		if current != nil {
+			current.finalize(c.alignment.end(current.Start, c.interval))
+			send(ctx, output, current)
+		}
+	}()
+
+	return output
+}
+
+// check rejects intervals that would fill gaps with empty bars forever.
+func (c *QuoteBarConfig) check() error {
+	if c.interval <= 0 {
+		return fmt.Errorf("interval must be positive, got %s", c.interval)
+	}
+
+	return nil
+}
+
+// Interface guards
+var _ QuoteProcessor = (*QuoteBarConfig)(nil)
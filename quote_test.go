@@ -0,0 +1,197 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	decimal "github.com/alpacahq/alpacadecimal"
+	"github.com/csgriffis/bartender"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func ohlc(o, h, l, c float64) bartender.OHLC {
+	return bartender.OHLC{
+		Open:  decimal.NewFromFloat(o),
+		High:  decimal.NewFromFloat(h),
+		Low:   decimal.NewFromFloat(l),
+		Close: decimal.NewFromFloat(c),
+	}
+}
+
+func TestQuoteBarConfig_ProcessQuotes(t *testing.T) {
+	tt := []struct {
+		name     string
+		interval time.Duration
+		quotes   []bartender.Quote
+		want     []bartender.QuoteBar
+	}{
+		{
+			name:     "Single Bar",
+			interval: time.Minute,
+			quotes: []bartender.Quote{
+				{Symbol: "AAPL", BidPrice: decimal.NewFromInt(99), AskPrice: decimal.NewFromInt(101), Time: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)},
+				{Symbol: "AAPL", BidPrice: decimal.NewFromInt(100), AskPrice: decimal.NewFromInt(101), Time: time.Date(2025, 1, 1, 10, 0, 30, 0, time.UTC)},
+			},
+			want: []bartender.QuoteBar{
+				{
+					Symbol: "AAPL",
+					Start:  time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+					Bid:    ohlc(99, 100, 99, 100),
+					Ask:    ohlc(101, 101, 101, 101),
+					Mid:    ohlc(100, 100.5, 100, 100.5),
+					Spread: decimal.NewFromFloat(1.5),
+					Quotes: 2,
+				},
+			},
+		},
+		{
+			name:     "Gap Carries Prevailing Quote",
+			interval: time.Minute,
+			quotes: []bartender.Quote{
+				{Symbol: "AAPL", BidPrice: decimal.NewFromInt(99), AskPrice: decimal.NewFromInt(101), Time: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)},
+				{Symbol: "AAPL", BidPrice: decimal.NewFromInt(100), AskPrice: decimal.NewFromInt(101), Time: time.Date(2025, 1, 1, 10, 0, 30, 0, time.UTC)},
+				{Symbol: "AAPL", BidPrice: decimal.NewFromInt(100), AskPrice: decimal.NewFromInt(102), Time: time.Date(2025, 1, 1, 10, 2, 15, 0, time.UTC)},
+			},
+			want: []bartender.QuoteBar{
+				{
+					Symbol: "AAPL",
+					Start:  time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+					Bid:    ohlc(99, 100, 99, 100),
+					Ask:    ohlc(101, 101, 101, 101),
+					Mid:    ohlc(100, 100.5, 100, 100.5),
+					Spread: decimal.NewFromFloat(1.5),
+					Quotes: 2,
+				},
+				{
+					Symbol: "AAPL",
+					Start:  time.Date(2025, 1, 1, 10, 1, 0, 0, time.UTC),
+					Bid:    ohlc(100, 100, 100, 100),
+					Ask:    ohlc(101, 101, 101, 101),
+					Mid:    ohlc(100.5, 100.5, 100.5, 100.5),
+					Spread: decimal.NewFromInt(1),
+				},
+				{
+					Symbol: "AAPL",
+					Start:  time.Date(2025, 1, 1, 10, 2, 0, 0, time.UTC),
+					Bid:    ohlc(100, 100, 100, 100),
+					Ask:    ohlc(101, 102, 101, 102),
+					Mid:    ohlc(100.5, 101, 100.5, 101),
+					Spread: decimal.NewFromFloat(1.75),
+					Quotes: 1,
+				},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := bartender.NewQuoteProcessor(bartender.WithQuoteInterval(tc.interval))
+			if err != nil {
+				t.Fatalf("NewQuoteProcessor() error = %v", err)
+			}
+
+			got, err := bartender.GenerateQuotes(tc.quotes, p)
+			if err != nil {
+				t.Fatalf("GenerateQuotes() error = %v", err)
+			}
+
+			if diff := cmp.Diff(got, tc.want, cmpopts.IgnoreUnexported(bartender.QuoteBar{})); diff != "" {
+				t.Errorf("GenerateQuotes() mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestQuoteBarConfig_InvalidInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Minute} {
+		if _, err := bartender.NewQuoteProcessor(bartender.WithQuoteInterval(interval)); err == nil {
+			t.Errorf("NewQuoteProcessor(WithQuoteInterval(%s)) error = nil, want error", interval)
+		}
+	}
+}
+
+func TestGenerateQuoteStream(t *testing.T) {
+	p, err := bartender.NewQuoteProcessor(bartender.WithQuoteInterval(time.Minute))
+	if err != nil {
+		t.Fatalf("NewQuoteProcessor() error = %v", err)
+	}
+
+	if _, err := bartender.GenerateQuoteStream(nil, p); err == nil {
+		t.Error("GenerateQuoteStream() error = nil, want error")
+	}
+
+	quotes := make(chan bartender.Quote, 1)
+	quotes <- bartender.Quote{BidPrice: decimal.NewFromInt(99), AskPrice: decimal.NewFromInt(101), Time: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)}
+	close(quotes)
+
+	barCh, err := bartender.GenerateQuoteStream(quotes, p)
+	if err != nil {
+		t.Fatalf("GenerateQuoteStream() error = %v", err)
+	}
+
+	var bars []bartender.QuoteBar
+	for bar := range barCh {
+		bars = append(bars, bar)
+	}
+
+	if len(bars) != 1 || !bars[0].Spread.Equal(decimal.NewFromInt(2)) {
+		t.Errorf("GenerateQuoteStream() = %+v, want a single bar with a spread of 2", bars)
+	}
+}
+
+func TestGenerateQuoteStreamContext(t *testing.T) {
+	p, err := bartender.NewQuoteProcessor(bartender.WithQuoteInterval(time.Second))
+	if err != nil {
+		t.Fatalf("NewQuoteProcessor() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// an endless quote stream that is never closed
+	quotes := make(chan bartender.Quote)
+	go func() {
+		for i := 0; ; i++ {
+			quote := bartender.Quote{BidPrice: decimal.NewFromInt(99), AskPrice: decimal.NewFromInt(101), Time: time.Date(2025, 1, 1, 10, 0, i, 0, time.UTC)}
+			select {
+			case quotes <- quote:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	barCh, errCh, err := bartender.GenerateQuoteStreamContext(ctx, quotes, p)
+	if err != nil {
+		t.Fatalf("GenerateQuoteStreamContext() error = %v", err)
+	}
+
+	for range 3 {
+		<-barCh
+	}
+	cancel()
+
+	// the bars are abandoned, so the stream must stop without a reader
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("GenerateQuoteStreamContext() stream error = %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("error channel was not sent to after cancellation")
+	}
+
+	if _, ok := <-barCh; ok {
+		t.Error("bar channel was not closed after cancellation")
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender
+
+import (
+	"context"
+	"fmt"
+
+	decimal "github.com/alpacahq/alpacadecimal"
+)
+
+func WithPriceRange(priceRange float64) Option[RangeBarConfig] {
+	return func(r *RangeBarConfig) {
+		r.priceRange = decimal.NewFromFloat(priceRange)
+	}
+}
+
+// RangeBarConfig closes a bar once the distance between its High and Low reaches the configured price range. The
+// trade that completes the range is included in the bar, so a gapping trade can produce a bar wider than the range.
+type RangeBarConfig struct {
+	processorConfig
+
+	priceRange decimal.Decimal `validate:"required"`
+}
+
+// check rejects price ranges that would close a bar on every trade.
+func (c *RangeBarConfig) check() error {
+	if !c.priceRange.IsPositive() {
+		return fmt.Errorf("price range must be positive, got %s", c.priceRange)
+	}
+
+	return nil
+}
+
+func (c RangeBarConfig) Process(trades <-chan Trade) chan *Bar {
+	return c.ProcessContext(context.Background(), trades)
+}
+
+func (c RangeBarConfig) ProcessContext(ctx context.Context, trades <-chan Trade) chan *Bar {
+	output := make(chan *Bar)
+
+	go func() {
+		defer close(output)
+
+		var current *Bar
+
+		// resume from the checkpoint, if any
+		var state accumulatorState
+		if c.restore(&state) {
+			current = state.Current.bar()
+		}
+
+		partial := c.partials()
+
+		for trade := range c.newTrades(ctx, trades) {
+			// initialize the current bar if it doesn't exist
+			if current == nil {
+				current = &Bar{}
+			}
+
+			// check if the trade is in a new session
+			if c.session.crossed(current.Start, trade.Time) {
+				finalizedBar := current
+
+				if !c.closeBar(ctx, output, finalizedBar, CloseReasonSession) {
+					return
+				}
+
+				// reset the current bar
+				current = &Bar{}
+			}
+
+			c.apply(current, trade)
+
+			if current.High.Sub(current.Low).GreaterThanOrEqual(c.priceRange) {
+				finalizedBar := current
+				if !c.closeBar(ctx, output, finalizedBar, CloseReasonThreshold) {
+					return
+				}
+
+				// reset the current bar
+				current = nil
+			}
+
+			if !partial.publish(ctx, output, trade, current) {
+				return
+			}
+
+			c.save(trade, accumulatorState{Current: snapshotBar(current)})
+		}
+
+		if current != nil {
+			c.closeBar(ctx, output, current, CloseReasonEndOfStream)
+		}
+	}()
+
+	return output
+}
+
+// Interface guards
+var _ Processor = (*RangeBarConfig)(nil)
+var _ ContextProcessor = (*RangeBarConfig)(nil)
@@ -0,0 +1,112 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender_test
+
+import (
+	"testing"
+	"time"
+
+	decimal "github.com/alpacahq/alpacadecimal"
+	"github.com/csgriffis/bartender"
+)
+
+func TestRangeBarConfig_Process(t *testing.T) {
+	tt := []TestCase[float64]{
+		{
+			name:  "Single Bar with No Range Trigger",
+			input: 5,
+			trades: []bartender.Trade{
+				{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)},
+				{Price: decimal.NewFromInt(102), Size: decimal.NewFromInt(1), Side: bartender.SideSell, Time: time.Date(2025, 1, 1, 10, 0, 30, 0, time.UTC)},
+			},
+			want: []bartender.Bar{
+				{
+					Open:       decimal.NewFromInt(100),
+					High:       decimal.NewFromInt(102),
+					Low:        decimal.NewFromInt(100),
+					Close:      decimal.NewFromInt(102),
+					Volume:     decimal.NewFromInt(2),
+					Start:      time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+					BuyVolume:  decimal.NewFromInt(1),
+					SellVolume: decimal.NewFromInt(1),
+					Ticks:      2,
+					Upticks:    1,
+				},
+			},
+		},
+		{
+			name:  "Multiple Bars with Range Trigger",
+			input: 2,
+			trades: []bartender.Trade{
+				{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)},
+				{Price: decimal.NewFromInt(101), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 10, 0, time.UTC)},
+				{Price: decimal.NewFromInt(102), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 20, 0, time.UTC)},
+				{Price: decimal.NewFromInt(101), Size: decimal.NewFromInt(2), Side: bartender.SideSell, Time: time.Date(2025, 1, 1, 10, 0, 30, 0, time.UTC)},
+				{Price: decimal.NewFromInt(99), Size: decimal.NewFromInt(3), Side: bartender.SideSell, Time: time.Date(2025, 1, 1, 10, 0, 40, 0, time.UTC)},
+				{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 50, 0, time.UTC)},
+			},
+			want: []bartender.Bar{
+				{
+					Open:      decimal.NewFromInt(100),
+					High:      decimal.NewFromInt(102),
+					Low:       decimal.NewFromInt(100),
+					Close:     decimal.NewFromInt(102),
+					Volume:    decimal.NewFromInt(3),
+					Start:     time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+					BuyVolume: decimal.NewFromInt(3),
+					Ticks:     3,
+					Upticks:   2,
+				},
+				{
+					Open:       decimal.NewFromInt(101),
+					High:       decimal.NewFromInt(101),
+					Low:        decimal.NewFromInt(99),
+					Close:      decimal.NewFromInt(99),
+					Volume:     decimal.NewFromInt(5),
+					Start:      time.Date(2025, 1, 1, 10, 0, 30, 0, time.UTC),
+					SellVolume: decimal.NewFromInt(5),
+					Ticks:      2,
+				},
+				{
+					Open:      decimal.NewFromInt(100),
+					High:      decimal.NewFromInt(100),
+					Low:       decimal.NewFromInt(100),
+					Close:     decimal.NewFromInt(100),
+					Volume:    decimal.NewFromInt(1),
+					Start:     time.Date(2025, 1, 1, 10, 0, 50, 0, time.UTC),
+					BuyVolume: decimal.NewFromInt(1),
+					Ticks:     1,
+				},
+			},
+		},
+		{
+			name:   "No Trades",
+			input:  2,
+			trades: []bartender.Trade{},
+			want:   []bartender.Bar{},
+		},
+	}
+
+	for _, tc := range tt {
+		p, err := bartender.New(bartender.WithPriceRange(tc.input))
+		if err != nil {
+			t.Errorf("New() error = %v", err)
+			return
+		}
+
+		tc.Run(t, p)
+	}
+}
+
+func TestRangeBarConfig_Invalid(t *testing.T) {
+	for _, priceRange := range []float64{0, -1} {
+		if _, err := bartender.New(bartender.WithPriceRange(priceRange)); err == nil {
+			t.Errorf("New(WithPriceRange(%v)) error = nil, want error", priceRange)
+		}
+	}
+}
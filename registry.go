@@ -0,0 +1,549 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gopkg.in/yaml.v3"
+)
+
+// registry maps processor type names to the factories that create them from a spec.
+var registry = struct {
+	sync.RWMutex
+	factories map[string]func(ProcessorSpec) (Processor, error)
+}{factories: make(map[string]func(ProcessorSpec) (Processor, error))}
+
+// Register makes a processor type available to NewFromSpec and ParseProcessor under name. The parameters of a spec
+// are decoded into the fields of the struct P, named by their json tags, and validated by their validate tags before
+// build creates the processor. Fields may be strings, booleans, integers, floats, time.Duration, implementations of
+// encoding.TextUnmarshaler, or pointers to them for optional parameters. Register panics if the name is already taken
+// or P is not a struct.
+func Register[P any](name string, build func(P) (Processor, error)) {
+	if reflect.TypeFor[P]().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("bartender: parameters of processor type %q are not a struct", name))
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	if _, ok := registry.factories[name]; ok || name == "" {
+		panic(fmt.Sprintf("bartender: processor type %q registered twice", name))
+	}
+
+	registry.factories[name] = func(spec ProcessorSpec) (Processor, error) {
+		var params P
+		if err := decodeParams(spec, &params); err != nil {
+			return nil, err
+		}
+
+		if err := validateParams(spec.Type, params); err != nil {
+			return nil, err
+		}
+
+		return build(params)
+	}
+}
+
+// ProcessorTypes returns the names of the registered processor types, in order.
+func ProcessorTypes() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	types := make([]string, 0, len(registry.factories))
+	for name := range registry.factories {
+		types = append(types, name)
+	}
+
+	slices.Sort(types)
+
+	return types
+}
+
+// ProcessorSpec names a registered processor type and its parameters, so that the type of bar can be chosen at run
+// time, for instance from a configuration file. Its text form is the type followed by comma-separated parameters,
+// such as "dollar:threshold=5e6,features=true", and its JSON and YAML form an object such as
+// {"type": "dollar", "threshold": 5e6}.
+type ProcessorSpec struct {
+	Type   string
+	Params map[string]string
+}
+
+// ParseProcessorSpec parses the text or JSON form of a spec.
+func ParseProcessorSpec(s string) (ProcessorSpec, error) {
+	s = strings.TrimSpace(s)
+
+	var spec ProcessorSpec
+	if strings.HasPrefix(s, "{") {
+		err := json.Unmarshal([]byte(s), &spec)
+
+		return spec, err
+	}
+
+	return spec, spec.UnmarshalText([]byte(s))
+}
+
+// ParseProcessor creates the Processor described by the text or JSON form of a spec.
+func ParseProcessor(s string) (Processor, error) {
+	spec, err := ParseProcessorSpec(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFromSpec(spec)
+}
+
+// NewFromSpec creates the Processor described by the spec. Invalid parameters are reported as SpecErrors, joined if
+// there are several.
+func NewFromSpec(spec ProcessorSpec) (Processor, error) {
+	registry.RLock()
+	factory, ok := registry.factories[spec.Type]
+	registry.RUnlock()
+
+	if !ok {
+		if spec.Type == "" {
+			return nil, errors.New("processor spec has no type")
+		}
+
+		return nil, fmt.Errorf("unknown processor type %q", spec.Type)
+	}
+
+	return factory(spec)
+}
+
+// String returns the text form of the spec, with its parameters in order.
+func (s ProcessorSpec) String() string {
+	params := make([]string, 0, len(s.Params))
+	for name, value := range s.Params {
+		params = append(params, name+"="+value)
+	}
+
+	if len(params) == 0 {
+		return s.Type
+	}
+
+	slices.Sort(params)
+
+	return s.Type + ":" + strings.Join(params, ",")
+}
+
+func (s ProcessorSpec) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *ProcessorSpec) UnmarshalText(text []byte) error {
+	kind, params, _ := strings.Cut(string(text), ":")
+
+	s.Type = strings.TrimSpace(kind)
+	s.Params = make(map[string]string)
+
+	if s.Type == "" {
+		return fmt.Errorf("processor spec %q has no type", text)
+	}
+
+	if strings.TrimSpace(params) == "" {
+		return nil
+	}
+
+	for _, param := range strings.Split(params, ",") {
+		name, value, ok := strings.Cut(param, "=")
+		if !ok {
+			return fmt.Errorf("invalid parameter %q of processor spec, want name=value", param)
+		}
+
+		if err := s.set(strings.TrimSpace(name), strings.TrimSpace(value)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s ProcessorSpec) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.object())
+}
+
+func (s *ProcessorSpec) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+
+	var object map[string]any
+	if err := decoder.Decode(&object); err != nil {
+		return err
+	}
+
+	return s.fromObject(object)
+}
+
+func (s ProcessorSpec) MarshalYAML() (any, error) {
+	return s.object(), nil
+}
+
+func (s *ProcessorSpec) UnmarshalYAML(value *yaml.Node) error {
+	var object map[string]any
+	if err := value.Decode(&object); err != nil {
+		return err
+	}
+
+	return s.fromObject(object)
+}
+
+// object returns the JSON and YAML form of the spec.
+func (s ProcessorSpec) object() map[string]string {
+	object := map[string]string{"type": s.Type}
+	for name, value := range s.Params {
+		object[name] = value
+	}
+
+	return object
+}
+
+// fromObject reads the spec from its decoded JSON or YAML form.
+func (s *ProcessorSpec) fromObject(object map[string]any) error {
+	kind, ok := object["type"].(string)
+	if !ok || kind == "" {
+		return errors.New("processor spec has no type")
+	}
+
+	s.Type = kind
+	s.Params = make(map[string]string)
+
+	for name, value := range object {
+		if name == "type" || value == nil {
+			continue
+		}
+
+		var text string
+		switch v := value.(type) {
+		case string:
+			text = v
+		case json.Number:
+			text = v.String()
+		case bool:
+			text = strconv.FormatBool(v)
+		case int:
+			text = strconv.Itoa(v)
+		case float64:
+			text = strconv.FormatFloat(v, 'g', -1, 64)
+		default:
+			return &SpecError{Type: kind, Param: name, Err: errors.New("must be a string, number or boolean")}
+		}
+
+		if err := s.set(name, text); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// set adds a parameter to the spec.
+func (s *ProcessorSpec) set(name, value string) error {
+	if _, ok := s.Params[name]; ok {
+		return &SpecError{Type: s.Type, Param: name, Err: errors.New("is given twice")}
+	}
+
+	s.Params[name] = value
+
+	return nil
+}
+
+// SpecError reports an invalid parameter of a ProcessorSpec.
+type SpecError struct {
+	// Type of processor the spec is for
+	Type string
+	// Param is the name of the invalid parameter
+	Param string
+	Err   error
+}
+
+func (e *SpecError) Error() string {
+	return fmt.Sprintf("%s: %s %v", e.Type, e.Param, e.Err)
+}
+
+func (e *SpecError) Unwrap() error {
+	return e.Err
+}
+
+// paramValidator validates the parameters of specs, naming fields by their json tags.
+var paramValidator = func() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(paramName)
+
+	return v
+}()
+
+// paramName returns the name of the spec parameter decoded into a field.
+func paramName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" {
+		return strings.ToLower(field.Name)
+	}
+
+	return name
+}
+
+// decodeParams decodes the parameters of the spec into the fields of the struct that params points to.
+func decodeParams(spec ProcessorSpec, params any) error {
+	fields := make(map[string]reflect.Value)
+	paramFields(reflect.ValueOf(params).Elem(), fields)
+
+	names := make([]string, 0, len(spec.Params))
+	for name := range spec.Params {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	var errs []error
+	for _, name := range names {
+		field, ok := fields[name]
+		if !ok {
+			errs = append(errs, &SpecError{Type: spec.Type, Param: name, Err: errors.New("is not a parameter")})
+			continue
+		}
+
+		if err := setParam(field, spec.Params[name]); err != nil {
+			errs = append(errs, &SpecError{Type: spec.Type, Param: name, Err: err})
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// paramFields adds the exported fields of the struct, including those of embedded structs, by parameter name.
+func paramFields(v reflect.Value, fields map[string]reflect.Value) {
+	t := v.Type()
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+
+		switch {
+		case field.Anonymous && field.Type.Kind() == reflect.Struct:
+			paramFields(v.Field(i), fields)
+		case field.IsExported():
+			fields[paramName(field)] = v.Field(i)
+		}
+	}
+}
+
+// setParam parses the text of a parameter into the field.
+func setParam(v reflect.Value, text string) error {
+	if v.Kind() == reflect.Pointer {
+		value := reflect.New(v.Type().Elem())
+		if err := setParam(value.Elem(), text); err != nil {
+			return err
+		}
+
+		v.Set(value)
+
+		return nil
+	}
+
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(text))
+	}
+
+	if v.Type() == reflect.TypeFor[time.Duration]() {
+		d, err := time.ParseDuration(text)
+		if err != nil {
+			return fmt.Errorf("must be a duration such as 1m30s, got %q", text)
+		}
+
+		v.SetInt(int64(d))
+
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(text)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return fmt.Errorf("must be true or false, got %q", text)
+		}
+
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		// whole numbers may be written in exponent form, such as 1e6
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil || f != math.Trunc(f) || v.OverflowInt(int64(f)) {
+			return fmt.Errorf("must be a whole number, got %q", text)
+		}
+
+		v.SetInt(int64(f))
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(text, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("must be a number, got %q", text)
+		}
+
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("has unsupported type %s", v.Type())
+	}
+
+	return nil
+}
+
+// validateParams checks the validate tags of the parameters, reporting each invalid field as a SpecError.
+func validateParams(kind string, params any) error {
+	err := paramValidator.Struct(params)
+
+	var invalid validator.ValidationErrors
+	if !errors.As(err, &invalid) {
+		return err
+	}
+
+	errs := make([]error, 0, len(invalid))
+	for _, field := range invalid {
+		errs = append(errs, &SpecError{Type: kind, Param: field.Field(), Err: errors.New(describeRule(field))})
+	}
+
+	return errors.Join(errs...)
+}
+
+// describeRule describes the validation rule a field broke.
+func describeRule(field validator.FieldError) string {
+	switch field.Tag() {
+	case "required":
+		return "is required"
+	case "gt":
+		return "must be greater than " + field.Param()
+	case "gte":
+		return "must be at least " + field.Param()
+	case "oneof":
+		return "must be one of " + field.Param()
+	}
+
+	return fmt.Sprintf("fails the %s rule", field.Tag())
+}
+
+// commonParams are the spec parameters shared by the built-in processor types.
+type commonParams struct {
+	// Features computes the microstructure features of every bar, see WithFeatures
+	Features bool `json:"features"`
+	// Partial emits provisional snapshots of the bar in progress at most once per throttle, see WithPartialBars
+	Partial *time.Duration `json:"partial" validate:"omitempty,gte=0"`
+}
+
+// commonOptions returns the options selected by the shared parameters.
+func commonOptions[T any, PT configurable[T]](p commonParams) []Option[T] {
+	var options []Option[T]
+
+	if p.Features {
+		options = append(options, WithFeatures[T, PT]())
+	}
+
+	if p.Partial != nil {
+		options = append(options, WithPartialBars[T, PT](*p.Partial))
+	}
+
+	return options
+}
+
+// timeParams are the spec parameters of time bars.
+type timeParams struct {
+	commonParams
+	Interval  time.Duration `json:"interval" validate:"required,gt=0"`
+	Revisions time.Duration `json:"revisions" validate:"gte=0"`
+}
+
+// thresholdParams are the spec parameters of tick, volume and dollar bars.
+type thresholdParams[N int64 | float64] struct {
+	commonParams
+	Threshold N `json:"threshold" validate:"required,gt=0"`
+}
+
+// adaptiveParams are the spec parameters of adaptive imbalance and run bars.
+type adaptiveParams struct {
+	commonParams
+	Warmup   int64 `json:"warmup" validate:"required,gt=0"`
+	Span     int64 `json:"span" validate:"gte=0"`
+	MinTicks int64 `json:"min_ticks" validate:"gte=0"`
+	MaxTicks int64 `json:"max_ticks" validate:"gte=0"`
+}
+
+// rangeParams are the spec parameters of range bars.
+type rangeParams struct {
+	commonParams
+	Range float64 `json:"range" validate:"required,gt=0"`
+}
+
+// renkoParams are the spec parameters of renko bricks.
+type renkoParams struct {
+	commonParams
+	Size     float64 `json:"size" validate:"required,gt=0"`
+	Reversal int64   `json:"reversal" validate:"gte=0"`
+}
+
+// registerThreshold registers a built-in processor type configured by a threshold.
+func registerThreshold[N int64 | float64, T Processor, PT configurable[T]](name string, option func(N) Option[T]) {
+	Register(name, func(p thresholdParams[N]) (Processor, error) {
+		return New(append(commonOptions[T, PT](p.commonParams), option(p.Threshold))...)
+	})
+}
+
+// registerAdaptive registers a built-in adaptive processor type.
+func registerAdaptive[T Processor, PT configurable[T]](name string, option, limits func(int64, int64) Option[T]) {
+	Register(name, func(p adaptiveParams) (Processor, error) {
+		options := append(commonOptions[T, PT](p.commonParams), option(p.Warmup, p.Span), limits(p.MinTicks, p.MaxTicks))
+
+		return New(options...)
+	})
+}
+
+func init() {
+	Register("time", func(p timeParams) (Processor, error) {
+		options := append(commonOptions[TimeBarConfig](p.commonParams), WithInterval(p.Interval), WithRevisions(p.Revisions))
+
+		return New(options...)
+	})
+
+	registerThreshold[int64, TickBarConfig]("tick", WithTickThreshold)
+	registerThreshold[int64, TickImbalanceBarConfig]("tick-imbalance", WithTickImbalanceThreshold)
+	registerThreshold[int64, TickRunsBarConfig]("tick-run", WithTickRunThreshold)
+	registerThreshold[float64, VolumeBarConfig]("volume", WithVolumeThreshold)
+	registerThreshold[float64, VolumeImbalanceBarConfig]("volume-imbalance", WithVolumeImbalanceThreshold)
+	registerThreshold[float64, VolumeRunBarConfig]("volume-run", WithVolumeRunThreshold)
+	registerThreshold[float64, DollarBarConfig]("dollar", WithDollarThreshold)
+	registerThreshold[float64, DollarImbalanceBarConfig]("dollar-imbalance", WithDollarImbalanceThreshold)
+	registerThreshold[float64, DollarRunBarConfig]("dollar-run", WithDollarRunThreshold)
+
+	registerAdaptive("adaptive-tick-imbalance", WithAdaptiveTickImbalance, WithAdaptiveTickImbalanceLimits)
+	registerAdaptive("adaptive-tick-run", WithAdaptiveTickRun, WithAdaptiveTickRunLimits)
+	registerAdaptive("adaptive-volume-imbalance", WithAdaptiveVolumeImbalance, WithAdaptiveVolumeImbalanceLimits)
+	registerAdaptive("adaptive-volume-run", WithAdaptiveVolumeRun, WithAdaptiveVolumeRunLimits)
+	registerAdaptive("adaptive-dollar-imbalance", WithAdaptiveDollarImbalance, WithAdaptiveDollarImbalanceLimits)
+	registerAdaptive("adaptive-dollar-run", WithAdaptiveDollarRun, WithAdaptiveDollarRunLimits)
+
+	Register("range", func(p rangeParams) (Processor, error) {
+		return New(append(commonOptions[RangeBarConfig](p.commonParams), WithPriceRange(p.Range))...)
+	})
+
+	Register("renko", func(p renkoParams) (Processor, error) {
+		options := append(commonOptions[RenkoConfig](p.commonParams), WithBrickSize(p.Size))
+		if p.Reversal > 0 {
+			options = append(options, WithReversal(p.Reversal))
+		}
+
+		return New(options...)
+	})
+}
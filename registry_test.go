@@ -0,0 +1,303 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	decimal "github.com/alpacahq/alpacadecimal"
+	"github.com/csgriffis/bartender"
+	"github.com/google/go-cmp/cmp"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseProcessorSpec(t *testing.T) {
+	tt := []struct {
+		name    string
+		input   string
+		want    bartender.ProcessorSpec
+		wantErr bool
+	}{
+		{
+			name:  "Text",
+			input: "dollar:threshold=5e6, features=true",
+			want:  bartender.ProcessorSpec{Type: "dollar", Params: map[string]string{"threshold": "5e6", "features": "true"}},
+		},
+		{
+			name:  "Type Only",
+			input: "tick",
+			want:  bartender.ProcessorSpec{Type: "tick", Params: map[string]string{}},
+		},
+		{
+			name:  "JSON",
+			input: `{"type": "time", "interval": "1m", "features": true, "partial": "0s"}`,
+			want:  bartender.ProcessorSpec{Type: "time", Params: map[string]string{"interval": "1m", "features": "true", "partial": "0s"}},
+		},
+		{
+			name:  "JSON Number",
+			input: `{"type": "volume", "threshold": 2.5e3}`,
+			want:  bartender.ProcessorSpec{Type: "volume", Params: map[string]string{"threshold": "2.5e3"}},
+		},
+		{name: "Missing Value", input: "dollar:threshold", wantErr: true},
+		{name: "Repeated Parameter", input: "dollar:threshold=1,threshold=2", wantErr: true},
+		{name: "Missing Type", input: ":threshold=1", wantErr: true},
+		{name: "JSON Missing Type", input: `{"threshold": 1}`, wantErr: true},
+		{name: "JSON Object Parameter", input: `{"type": "dollar", "threshold": {"value": 1}}`, wantErr: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := bartender.ParseProcessorSpec(tc.input)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseProcessorSpec() error = %v, wantErr %v", err, tc.wantErr)
+			}
+
+			if tc.wantErr {
+				return
+			}
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ParseProcessorSpec() (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestProcessorSpec_Encoding(t *testing.T) {
+	spec := bartender.ProcessorSpec{Type: "renko", Params: map[string]string{"size": "0.5", "reversal": "3"}}
+
+	if got, want := spec.String(), "renko:reversal=3,size=0.5"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	data, err := json.Marshal(spec)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var fromJSON bartender.ProcessorSpec
+	if err := json.Unmarshal(data, &fromJSON); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if diff := cmp.Diff(spec, fromJSON); diff != "" {
+		t.Errorf("JSON round trip (-want +got):\n%s", diff)
+	}
+
+	var config struct {
+		Bars []bartender.ProcessorSpec `yaml:"bars"`
+	}
+
+	input := "bars:\n  - type: dollar\n    threshold: 5e6\n  - type: time\n    interval: 5m\n    features: true\n"
+	if err := yaml.Unmarshal([]byte(input), &config); err != nil {
+		t.Fatalf("yaml.Unmarshal() error = %v", err)
+	}
+
+	want := []bartender.ProcessorSpec{
+		{Type: "dollar", Params: map[string]string{"threshold": "5e+06"}},
+		{Type: "time", Params: map[string]string{"interval": "5m", "features": "true"}},
+	}
+
+	if diff := cmp.Diff(want, config.Bars); diff != "" {
+		t.Errorf("YAML specs (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseProcessor(t *testing.T) {
+	tt := []struct {
+		name  string
+		input string
+		want  bartender.Processor
+	}{
+		{"Time", "time:interval=1m,revisions=5s", mustNew(bartender.WithInterval(time.Minute), bartender.WithRevisions(5*time.Second))},
+		{"Tick Exponent", "tick:threshold=1e3", mustNew(bartender.WithTickThreshold(1000))},
+		{"Dollar", `{"type": "dollar", "threshold": 5e6}`, mustNew(bartender.WithDollarThreshold(5e6))},
+		{
+			name:  "Shared Parameters",
+			input: "volume-run:threshold=100,features=true,partial=1s",
+			want: mustNew(
+				bartender.WithVolumeRunThreshold(100),
+				bartender.WithFeatures[bartender.VolumeRunBarConfig](),
+				bartender.WithPartialBars[bartender.VolumeRunBarConfig](time.Second),
+			),
+		},
+		{
+			name:  "Adaptive",
+			input: "adaptive-dollar-run:warmup=50,span=20,min_ticks=10,max_ticks=500",
+			want: mustNew(
+				bartender.WithAdaptiveDollarRun(50, 20),
+				bartender.WithAdaptiveDollarRunLimits(10, 500),
+			),
+		},
+		{"Range", "range:range=0.25", mustNew(bartender.WithPriceRange(0.25))},
+		{"Renko", "renko:size=0.5,reversal=3", mustNew(bartender.WithBrickSize(0.5), bartender.WithReversal(3))},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := bartender.ParseProcessor(tc.input)
+			if err != nil {
+				t.Fatalf("ParseProcessor() error = %v", err)
+			}
+
+			// configurations are compared field by field, including unexported fields
+			if !reflect.DeepEqual(tc.want, got) {
+				t.Errorf("ParseProcessor() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseProcessor_Errors(t *testing.T) {
+	tt := []struct {
+		name  string
+		input string
+		want  []bartender.SpecError
+	}{
+		{"Unknown Type", "heikin-ashi:threshold=1", nil},
+		{"Missing Threshold", "dollar", []bartender.SpecError{{Type: "dollar", Param: "threshold"}}},
+		{"Negative Threshold", "dollar:threshold=-1", []bartender.SpecError{{Type: "dollar", Param: "threshold"}}},
+		{"Fractional Ticks", "tick:threshold=1.5", []bartender.SpecError{{Type: "tick", Param: "threshold"}}},
+		{"Invalid Duration", "time:interval=soon", []bartender.SpecError{{Type: "time", Param: "interval"}}},
+		{"Unknown Parameter", "range:range=1,size=2", []bartender.SpecError{{Type: "range", Param: "size"}}},
+		{
+			name:  "Several",
+			input: "adaptive-tick-imbalance:span=-1,partial=-1s",
+			want: []bartender.SpecError{
+				{Type: "adaptive-tick-imbalance", Param: "partial"},
+				{Type: "adaptive-tick-imbalance", Param: "warmup"},
+				{Type: "adaptive-tick-imbalance", Param: "span"},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := bartender.ParseProcessor(tc.input)
+			if err == nil {
+				t.Fatal("ParseProcessor() error = nil, want error")
+			}
+
+			var got []bartender.SpecError
+			for _, e := range unwrapAll(err) {
+				var specErr *bartender.SpecError
+				if errors.As(e, &specErr) {
+					got = append(got, bartender.SpecError{Type: specErr.Type, Param: specErr.Param})
+				}
+			}
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ParseProcessor() errors (-want +got):\n%s\n%v", diff, err)
+			}
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	type spreadParams struct {
+		Spread float64 `json:"spread" validate:"required,gt=0"`
+		Name   string  `json:"name"`
+	}
+
+	// the registry outlives a single run of the test
+	if !slices.Contains(bartender.ProcessorTypes(), "test-spread") {
+		bartender.Register("test-spread", func(p spreadParams) (bartender.Processor, error) {
+			return bartender.New(bartender.WithPriceRange(p.Spread))
+		})
+	}
+
+	got, err := bartender.ParseProcessor("test-spread:spread=2,name=wide")
+	if err != nil {
+		t.Fatalf("ParseProcessor() error = %v", err)
+	}
+
+	if want := mustNew(bartender.WithPriceRange(2)); !reflect.DeepEqual(want, got) {
+		t.Errorf("ParseProcessor() = %+v, want %+v", got, want)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Register() did not panic for a type registered twice")
+		}
+	}()
+
+	bartender.Register("dollar", func(p spreadParams) (bartender.Processor, error) {
+		return nil, nil
+	})
+}
+
+func TestParseProcessor_Generate(t *testing.T) {
+	p, err := bartender.ParseProcessor("tick:threshold=2")
+	if err != nil {
+		t.Fatalf("ParseProcessor() error = %v", err)
+	}
+
+	trades := []bartender.Trade{
+		{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1)},
+		{Price: decimal.NewFromInt(101), Size: decimal.NewFromInt(1)},
+	}
+
+	bars, err := bartender.Generate(trades, p)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if len(bars) != 1 || bars[0].Processor != "TickBarConfig" {
+		t.Errorf("Generate() = %+v, want a single TickBarConfig bar", bars)
+	}
+}
+
+// mustNew creates a processor, failing on invalid options.
+func mustNew[T bartender.Processor](options ...bartender.Option[T]) bartender.Processor {
+	p, err := bartender.New(options...)
+	if err != nil {
+		panic(err)
+	}
+
+	return p
+}
+
+// unwrapAll flattens joined errors.
+func unwrapAll(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var errs []error
+		for _, e := range joined.Unwrap() {
+			errs = append(errs, unwrapAll(e)...)
+		}
+
+		return errs
+	}
+
+	return []error{err}
+}
+
+func TestProcessorTypes(t *testing.T) {
+	types := bartender.ProcessorTypes()
+
+	for _, want := range []string{"time", "tick", "volume-run", "dollar-imbalance", "adaptive-tick-run", "range", "renko"} {
+		if !slices.Contains(types, want) {
+			t.Errorf("ProcessorTypes() = %v, missing %q", types, want)
+		}
+	}
+
+	for _, kind := range types {
+		if kind == "test-spread" {
+			continue
+		}
+
+		spec := bartender.ProcessorSpec{Type: kind}
+		if _, err := bartender.NewFromSpec(spec); err == nil {
+			t.Errorf("NewFromSpec(%q) error = nil, want error for missing parameters", kind)
+		}
+	}
+}
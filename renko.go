@@ -0,0 +1,177 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	decimal "github.com/alpacahq/alpacadecimal"
+)
+
+// defaultReversal is the classic two brick reversal.
+const defaultReversal = 2
+
+func WithBrickSize(size float64) Option[RenkoConfig] {
+	return func(r *RenkoConfig) {
+		r.brickSize = decimal.NewFromFloat(size)
+	}
+}
+
+// WithReversal sets the number of bricks price must move against the current direction before a reversal brick is
+// drawn. Defaults to 2.
+func WithReversal(bricks int64) Option[RenkoConfig] {
+	return func(r *RenkoConfig) {
+		r.reversal = bricks
+	}
+}
+
+// RenkoConfig emits fixed size bricks. Each brick is a Bar whose Open and Close are the brick boundaries, while the
+// volume and tick statistics cover the trades seen since the previous brick. A trade that moves price several bricks
+// emits one brick per box; the additional bricks carry no trades. Trades that have not completed a brick when the
+// trade channel closes are not emitted. A new session restarts the bricks from its first trade, dropping any trades
+// that have not completed a brick in the previous session.
+type RenkoConfig struct {
+	processorConfig
+
+	brickSize decimal.Decimal `validate:"required"`
+	reversal  int64
+}
+
+// check rejects brick sizes that would draw bricks forever and negative reversals.
+func (c *RenkoConfig) check() error {
+	if !c.brickSize.IsPositive() {
+		return fmt.Errorf("brick size must be positive, got %s", c.brickSize)
+	}
+
+	if c.reversal < 0 {
+		return fmt.Errorf("reversal must not be negative, got %d", c.reversal)
+	}
+
+	return nil
+}
+
+func (c RenkoConfig) Process(trades <-chan Trade) chan *Bar {
+	return c.ProcessContext(context.Background(), trades)
+}
+
+func (c RenkoConfig) ProcessContext(ctx context.Context, trades <-chan Trade) chan *Bar {
+	output := make(chan *Bar)
+
+	go func() {
+		defer close(output)
+
+		var current *Bar
+		var last decimal.Decimal // close of the last brick
+		var direction int        // 1 for up bricks, -1 for down bricks
+		var lastTrade time.Time
+
+		// resume from the checkpoint, if any
+		var state renkoState
+		if c.restore(&state) {
+			current, last, direction, lastTrade = state.Current.bar(), state.Last, state.Direction, state.LastTrade
+		}
+
+		partial := c.partials()
+
+		for trade := range c.newTrades(ctx, trades) {
+			// check if the trade is in a new session
+			if c.session.crossed(lastTrade, trade.Time) {
+				current = nil
+				last = decimal.Zero
+				direction = 0
+			}
+
+			lastTrade = trade.Time
+
+			// the first trade is the reference for the first brick
+			if last.IsZero() {
+				last = trade.Price
+			}
+
+			if current == nil {
+				current = &Bar{}
+			}
+
+			c.apply(current, trade)
+
+			// draw every brick the trade completes
+			for {
+				brickOpen, brickClose, next, ok := c.nextBrick(trade.Price, last, direction)
+				if !ok {
+					break
+				}
+
+				brick := current
+				if brick == nil {
+					brick = &Bar{Symbol: trade.Symbol, Start: trade.Time, End: trade.Time}
+				}
+
+				brick.Open = brickOpen
+				brick.Close = brickClose
+				brick.High = decimal.Max(brickOpen, brickClose)
+				brick.Low = decimal.Min(brickOpen, brickClose)
+
+				if !c.closeBar(ctx, output, brick, CloseReasonThreshold) {
+					return
+				}
+
+				current = nil
+				last = brickClose
+				direction = next
+			}
+
+			if !partial.publish(ctx, output, trade, current) {
+				return
+			}
+
+			c.save(trade, renkoState{Current: snapshotBar(current), Last: last, Direction: direction, LastTrade: lastTrade})
+		}
+	}()
+
+	return output
+}
+
+// nextBrick determines the brick completed by price given the close and direction of the last brick.
+func (c RenkoConfig) nextBrick(price, last decimal.Decimal, direction int) (brickOpen, brickClose decimal.Decimal, next int, ok bool) {
+	reversal := c.reversal
+	if reversal <= 0 {
+		reversal = defaultReversal
+	}
+
+	// a reversal brick opens (reversal - 1) bricks away from the last close
+	reversalOffset := c.brickSize.Mul(decimal.NewFromInt(reversal - 1))
+
+	switch {
+	case direction >= 0 && price.GreaterThanOrEqual(last.Add(c.brickSize)):
+		return last, last.Add(c.brickSize), 1, true
+	case direction <= 0 && price.LessThanOrEqual(last.Sub(c.brickSize)):
+		return last, last.Sub(c.brickSize), -1, true
+	case direction > 0 && price.LessThanOrEqual(last.Sub(reversalOffset).Sub(c.brickSize)):
+		brickOpen = last.Sub(reversalOffset)
+		return brickOpen, brickOpen.Sub(c.brickSize), -1, true
+	case direction < 0 && price.GreaterThanOrEqual(last.Add(reversalOffset).Add(c.brickSize)):
+		brickOpen = last.Add(reversalOffset)
+		return brickOpen, brickOpen.Add(c.brickSize), 1, true
+	}
+
+	return decimal.Zero, decimal.Zero, direction, false
+}
+
+// renkoState is the state of a RenkoConfig processor between trades.
+type renkoState struct {
+	Current   *barState       `json:"current,omitempty"`
+	Last      decimal.Decimal `json:"last"`
+	Direction int             `json:"direction"`
+	LastTrade time.Time       `json:"last_trade"`
+}
+
+// Interface guards
+var _ Processor = (*RenkoConfig)(nil)
+var _ ContextProcessor = (*RenkoConfig)(nil)
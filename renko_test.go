@@ -0,0 +1,154 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender_test
+
+import (
+	"testing"
+	"time"
+
+	decimal "github.com/alpacahq/alpacadecimal"
+	"github.com/csgriffis/bartender"
+)
+
+func TestRenkoConfig_Process(t *testing.T) {
+	type renkoInput struct {
+		brickSize float64
+		reversal  int64
+	}
+
+	tt := []TestCase[renkoInput]{
+		{
+			name:  "No Brick Completed",
+			input: renkoInput{brickSize: 1, reversal: 2},
+			trades: []bartender.Trade{
+				{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)},
+				{Price: decimal.NewFromFloat(100.5), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 10, 0, time.UTC)},
+			},
+			want: []bartender.Bar{},
+		},
+		{
+			name:  "Multiple Bricks and Reversal",
+			input: renkoInput{brickSize: 1, reversal: 2},
+			trades: []bartender.Trade{
+				{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)},
+				{Price: decimal.NewFromFloat(100.5), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 10, 0, time.UTC)},
+				{Price: decimal.NewFromInt(101), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 20, 0, time.UTC)},
+				{Price: decimal.NewFromFloat(103.2), Size: decimal.NewFromInt(2), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 30, 0, time.UTC)},
+				{Price: decimal.NewFromInt(102), Size: decimal.NewFromInt(1), Side: bartender.SideSell, Time: time.Date(2025, 1, 1, 10, 0, 40, 0, time.UTC)},
+				{Price: decimal.NewFromFloat(100.9), Size: decimal.NewFromInt(1), Side: bartender.SideSell, Time: time.Date(2025, 1, 1, 10, 0, 50, 0, time.UTC)},
+			},
+			want: []bartender.Bar{
+				{
+					Open:      decimal.NewFromInt(100),
+					High:      decimal.NewFromInt(101),
+					Low:       decimal.NewFromInt(100),
+					Close:     decimal.NewFromInt(101),
+					Volume:    decimal.NewFromInt(3),
+					Start:     time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+					BuyVolume: decimal.NewFromInt(3),
+					Ticks:     3,
+					Upticks:   2,
+				},
+				{
+					Open:      decimal.NewFromInt(101),
+					High:      decimal.NewFromInt(102),
+					Low:       decimal.NewFromInt(101),
+					Close:     decimal.NewFromInt(102),
+					Volume:    decimal.NewFromInt(2),
+					Start:     time.Date(2025, 1, 1, 10, 0, 30, 0, time.UTC),
+					BuyVolume: decimal.NewFromInt(2),
+					Ticks:     1,
+				},
+				{
+					Open:  decimal.NewFromInt(102),
+					High:  decimal.NewFromInt(103),
+					Low:   decimal.NewFromInt(102),
+					Close: decimal.NewFromInt(103),
+					Start: time.Date(2025, 1, 1, 10, 0, 30, 0, time.UTC),
+				},
+				{
+					Open:       decimal.NewFromInt(102),
+					High:       decimal.NewFromInt(102),
+					Low:        decimal.NewFromInt(101),
+					Close:      decimal.NewFromInt(101),
+					Volume:     decimal.NewFromInt(2),
+					Start:      time.Date(2025, 1, 1, 10, 0, 40, 0, time.UTC),
+					SellVolume: decimal.NewFromInt(2),
+					Ticks:      2,
+				},
+			},
+		},
+		{
+			name:  "Single Brick Reversal",
+			input: renkoInput{brickSize: 1, reversal: 1},
+			trades: []bartender.Trade{
+				{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)},
+				{Price: decimal.NewFromInt(99), Size: decimal.NewFromInt(1), Side: bartender.SideSell, Time: time.Date(2025, 1, 1, 10, 0, 10, 0, time.UTC)},
+				{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 20, 0, time.UTC)},
+			},
+			want: []bartender.Bar{
+				{
+					Open:       decimal.NewFromInt(100),
+					High:       decimal.NewFromInt(100),
+					Low:        decimal.NewFromInt(99),
+					Close:      decimal.NewFromInt(99),
+					Volume:     decimal.NewFromInt(2),
+					Start:      time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+					BuyVolume:  decimal.NewFromInt(1),
+					SellVolume: decimal.NewFromInt(1),
+					Ticks:      2,
+				},
+				{
+					Open:      decimal.NewFromInt(99),
+					High:      decimal.NewFromInt(100),
+					Low:       decimal.NewFromInt(99),
+					Close:     decimal.NewFromInt(100),
+					Volume:    decimal.NewFromInt(1),
+					Start:     time.Date(2025, 1, 1, 10, 0, 20, 0, time.UTC),
+					BuyVolume: decimal.NewFromInt(1),
+					Ticks:     1,
+				},
+			},
+		},
+		{
+			name:   "No Trades",
+			input:  renkoInput{brickSize: 1},
+			trades: []bartender.Trade{},
+			want:   []bartender.Bar{},
+		},
+	}
+
+	for _, tc := range tt {
+		p, err := bartender.New(bartender.WithBrickSize(tc.input.brickSize), bartender.WithReversal(tc.input.reversal))
+		if err != nil {
+			t.Errorf("New() error = %v", err)
+			return
+		}
+
+		tc.Run(t, p)
+	}
+}
+
+func TestRenkoConfig_Invalid(t *testing.T) {
+	tt := []struct {
+		name    string
+		options []bartender.Option[bartender.RenkoConfig]
+	}{
+		{"Zero Brick Size", []bartender.Option[bartender.RenkoConfig]{bartender.WithBrickSize(0)}},
+		{"Negative Brick Size", []bartender.Option[bartender.RenkoConfig]{bartender.WithBrickSize(-1)}},
+		{"Negative Reversal", []bartender.Option[bartender.RenkoConfig]{bartender.WithBrickSize(1), bartender.WithReversal(-1)}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := bartender.New(tc.options...); err == nil {
+				t.Error("New() error = nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender
+
+import (
+	"container/heap"
+	"context"
+	"slices"
+	"time"
+)
+
+// LatePolicy decides what happens to a trade that arrives after the watermark has passed its time. The zero value
+// drops late trades.
+type LatePolicy struct {
+	deadLetter chan<- Trade
+	correct    bool
+}
+
+// DropLate discards late trades.
+func DropLate() LatePolicy {
+	return LatePolicy{}
+}
+
+// DeadLetter sends late trades to the channel instead of the processor. Sends block until the trade is received or
+// the stream is cancelled.
+func DeadLetter(trades chan<- Trade) LatePolicy {
+	return LatePolicy{deadLetter: trades}
+}
+
+// CorrectLate passes late trades on to the processor out of order. Time bars configured WithRevisions emit a revised
+// bar for them, while other processors add them to the bar in progress.
+func CorrectLate() LatePolicy {
+	return LatePolicy{correct: true}
+}
+
+// Reordered returns a Processor that sorts trades by time before passing them to the processor. Trades are held back
+// until the watermark, the latest trade time seen less the allowed lateness, has passed them, so trades arriving up
+// to lateness out of order reach the processor in time order. Trades older than the watermark are handled by the
+// policy. The watermark only advances with new trades, and the held back trades are flushed when the trade channel
+// is closed. Cancels and corrections are applied to the trade they refer to while it is held back, and passed on
+// straight away otherwise.
+func Reordered(processor Processor, lateness time.Duration, policy LatePolicy) Processor {
+	return reorderedProcessor{processor: processor, lateness: lateness, policy: policy}
+}
+
+type reorderedProcessor struct {
+	processor Processor
+	lateness  time.Duration
+	policy    LatePolicy
+}
+
+func (r reorderedProcessor) Process(trades <-chan Trade) chan *Bar {
+	return r.ProcessContext(context.Background(), trades)
+}
+
+func (r reorderedProcessor) ProcessContext(ctx context.Context, trades <-chan Trade) chan *Bar {
+	return process(ctx, r.processor, r.reorder(ctx, trades))
+}
+
+// reorder returns the trades in time order, applying the late policy to trades older than the watermark.
+func (r reorderedProcessor) reorder(ctx context.Context, trades <-chan Trade) <-chan Trade {
+	output := make(chan Trade)
+
+	go func() {
+		defer close(output)
+
+		var pending reorderQueue
+		var watermark time.Time
+		var arrivals int64
+
+		for trade := range receive(ctx, trades) {
+			// is the trade a cancel or correction?
+			if trade.Action != "" {
+				i := slices.IndexFunc(pending, func(p reorderedTrade) bool {
+					return trade.ID != "" && p.ID == trade.ID
+				})
+
+				// pass it on if the trade it refers to has already been released
+				if i < 0 {
+					if !send(ctx, output, trade) {
+						return
+					}
+
+					continue
+				}
+
+				// otherwise apply it to the held back trade
+				heap.Remove(&pending, i)
+
+				if trade.Action == ActionCancel {
+					continue
+				}
+
+				trade.Action = ""
+			}
+
+			// is the trade older than the watermark?
+			if !watermark.IsZero() && trade.Time.Before(watermark) {
+				if !r.late(ctx, output, trade) {
+					return
+				}
+
+				continue
+			}
+
+			heap.Push(&pending, reorderedTrade{Trade: trade, arrival: arrivals})
+			arrivals++
+
+			if next := trade.Time.Add(-r.lateness); next.After(watermark) {
+				watermark = next
+			}
+
+			// release the trades the watermark has passed
+			for pending.Len() > 0 && !pending[0].Time.After(watermark) {
+				if !send(ctx, output, heap.Pop(&pending).(reorderedTrade).Trade) {
+					return
+				}
+			}
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		for pending.Len() > 0 {
+			if !send(ctx, output, heap.Pop(&pending).(reorderedTrade).Trade) {
+				return
+			}
+		}
+	}()
+
+	return output
+}
+
+// late applies the late policy to the trade, reporting false if the context was cancelled.
+func (r reorderedProcessor) late(ctx context.Context, output chan<- Trade, trade Trade) bool {
+	switch {
+	case r.policy.correct:
+		return send(ctx, output, trade)
+	case r.policy.deadLetter != nil:
+		return send(ctx, r.policy.deadLetter, trade)
+	}
+
+	return true
+}
+
+// reorderedTrade is a trade held back by the reordering stage, along with its arrival order to keep trades with the
+// same time in the order they arrived.
+type reorderedTrade struct {
+	Trade
+	arrival int64
+}
+
+// reorderQueue is a min-heap of held back trades ordered by time and arrival.
+type reorderQueue []reorderedTrade
+
+func (q reorderQueue) Len() int {
+	return len(q)
+}
+
+func (q reorderQueue) Less(i, j int) bool {
+	if q[i].Time.Equal(q[j].Time) {
+		return q[i].arrival < q[j].arrival
+	}
+
+	return q[i].Time.Before(q[j].Time)
+}
+
+func (q reorderQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *reorderQueue) Push(x any) {
+	*q = append(*q, x.(reorderedTrade))
+}
+
+func (q *reorderQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+
+	return item
+}
+
+// Interface guards
+var _ Processor = (*reorderedProcessor)(nil)
+var _ ContextProcessor = (*reorderedProcessor)(nil)
+var _ heap.Interface = (*reorderQueue)(nil)
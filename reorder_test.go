@@ -0,0 +1,195 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender_test
+
+import (
+	"testing"
+	"time"
+
+	decimal "github.com/alpacahq/alpacadecimal"
+	"github.com/csgriffis/bartender"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// passthroughPrices returns the close of every bar a processor emits.
+func passthroughPrices(t *testing.T, p bartender.Processor, trades []bartender.Trade) []int64 {
+	t.Helper()
+
+	bars, err := bartender.Generate(trades, p)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	prices := make([]int64, 0, len(bars))
+	for _, bar := range bars {
+		prices = append(prices, bar.Close.IntPart())
+	}
+
+	return prices
+}
+
+func reorderTrade(price int64, seconds int) bartender.Trade {
+	return bartender.Trade{
+		Price: decimal.NewFromInt(price),
+		Size:  decimal.NewFromInt(1),
+		Side:  bartender.SideBuy,
+		Time:  time.Date(2025, 1, 1, 10, 0, seconds, 0, time.UTC),
+	}
+}
+
+func TestReordered(t *testing.T) {
+	trades := []bartender.Trade{
+		reorderTrade(1, 0),
+		reorderTrade(3, 3),
+		reorderTrade(2, 2), // within the lateness
+		reorderTrade(4, 3), // same time as an earlier trade
+		reorderTrade(6, 10),
+		reorderTrade(5, 4), // behind the watermark
+		reorderTrade(7, 12),
+	}
+
+	tt := []struct {
+		name   string
+		policy bartender.LatePolicy
+		want   []int64
+	}{
+		{name: "Drop", policy: bartender.DropLate(), want: []int64{1, 2, 3, 4, 6, 7}},
+		{name: "Correct", policy: bartender.CorrectLate(), want: []int64{1, 2, 3, 4, 5, 6, 7}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			p := bartender.Reordered(passthroughProcessor{}, 5*time.Second, tc.policy)
+
+			if diff := cmp.Diff(tc.want, passthroughPrices(t, p, trades)); diff != "" {
+				t.Errorf("Generate() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReordered_DeadLetter(t *testing.T) {
+	trades := []bartender.Trade{
+		reorderTrade(1, 0),
+		reorderTrade(2, 10),
+		reorderTrade(3, 1),
+		reorderTrade(4, 11),
+	}
+
+	deadLetters := make(chan bartender.Trade, len(trades))
+	p := bartender.Reordered(passthroughProcessor{}, 5*time.Second, bartender.DeadLetter(deadLetters))
+
+	if diff := cmp.Diff([]int64{1, 2, 4}, passthroughPrices(t, p, trades)); diff != "" {
+		t.Errorf("Generate() mismatch (-want +got):\n%s", diff)
+	}
+
+	close(deadLetters)
+
+	var got []bartender.Trade
+	for trade := range deadLetters {
+		got = append(got, trade)
+	}
+
+	if diff := cmp.Diff([]bartender.Trade{trades[2]}, got); diff != "" {
+		t.Errorf("dead letters mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestReordered_Revisions(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	timeBars, err := bartender.New(bartender.WithInterval(time.Minute), bartender.WithRevisions(10*time.Minute))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	p := bartender.Reordered(timeBars, 10*time.Second, bartender.CorrectLate())
+
+	trades := []bartender.Trade{
+		{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: start.Add(10 * time.Second)},
+		{Price: decimal.NewFromInt(102), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: start.Add(70 * time.Second)},
+		{Price: decimal.NewFromInt(103), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: start.Add(90 * time.Second)},
+		// late for the first bar, which has been emitted by now
+		{Price: decimal.NewFromInt(99), Size: decimal.NewFromInt(2), Side: bartender.SideSell, Time: start.Add(5 * time.Second)},
+	}
+
+	want := []bartender.Bar{
+		{
+			Open:      decimal.NewFromInt(100),
+			High:      decimal.NewFromInt(100),
+			Low:       decimal.NewFromInt(100),
+			Close:     decimal.NewFromInt(100),
+			Volume:    decimal.NewFromInt(1),
+			Start:     start,
+			BuyVolume: decimal.NewFromInt(1),
+			Ticks:     1,
+		},
+		{
+			Open:       decimal.NewFromInt(99),
+			High:       decimal.NewFromInt(100),
+			Low:        decimal.NewFromInt(99),
+			Close:      decimal.NewFromInt(100),
+			Volume:     decimal.NewFromInt(3),
+			Start:      start,
+			BuyVolume:  decimal.NewFromInt(1),
+			SellVolume: decimal.NewFromInt(2),
+			Ticks:      2,
+			Upticks:    1,
+			Revision:   1,
+		},
+		{
+			Open:      decimal.NewFromInt(102),
+			High:      decimal.NewFromInt(103),
+			Low:       decimal.NewFromInt(102),
+			Close:     decimal.NewFromInt(103),
+			Volume:    decimal.NewFromInt(2),
+			Start:     start.Add(time.Minute),
+			BuyVolume: decimal.NewFromInt(2),
+			Ticks:     2,
+			Upticks:   1,
+		},
+	}
+
+	got, err := bartender.Generate(trades, p)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreUnexported(bartender.Bar{}), ignoreBarStats); diff != "" {
+		t.Errorf("Generate() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestReordered_Amendments(t *testing.T) {
+	cancel := reorderTrade(0, 1)
+	cancel.ID, cancel.Action = "2", bartender.ActionCancel
+
+	correct := reorderTrade(4, 3)
+	correct.ID, correct.Action = "3", bartender.ActionCorrect
+
+	released := reorderTrade(0, 20)
+	released.ID, released.Action = "1", bartender.ActionCancel
+
+	trades := []bartender.Trade{
+		reorderTrade(1, 0),
+		reorderTrade(2, 1),
+		reorderTrade(3, 2),
+		cancel,  // applied to the held back trade
+		correct, // applied to the held back trade
+		reorderTrade(5, 10),
+		released, // passed on straight away
+	}
+	trades[0].ID, trades[1].ID, trades[2].ID = "1", "2", "3"
+
+	p := bartender.Reordered(passthroughProcessor{}, 5*time.Second, bartender.DropLate())
+
+	if diff := cmp.Diff([]int64{1, 4, 0, 5}, passthroughPrices(t, p, trades)); diff != "" {
+		t.Errorf("Generate() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender
+
+import (
+	"slices"
+	"sort"
+)
+
+// revisableBar is an emitted bar along with its trades, kept so the bar can be revised when a trade arrives late.
+type revisableBar struct {
+	bar    *Bar
+	trades []Trade
+}
+
+// revise adds the trade to the bar and replaces the bar with a new one, built from all of its trades, with the next
+// revision number.
+func (r *revisableBar) revise(t Trade) *Bar {
+	r.trades = insertTrade(r.trades, t)
+	r.bar = rebuildBar(r.bar, r.trades)
+	r.bar.Revision++
+
+	return r.bar
+}
+
+// revisableBarState is the serialized form of a revisableBar.
+type revisableBarState struct {
+	Bar    *barState `json:"bar"`
+	Trades []Trade   `json:"trades,omitempty"`
+}
+
+func snapshotRevisable(bars []revisableBar) []revisableBarState {
+	states := make([]revisableBarState, 0, len(bars))
+	for _, r := range bars {
+		states = append(states, revisableBarState{Bar: snapshotBar(r.bar), Trades: r.trades})
+	}
+
+	return states
+}
+
+func restoreRevisable(states []revisableBarState) []revisableBar {
+	bars := make([]revisableBar, 0, len(states))
+	for _, s := range states {
+		bars = append(bars, revisableBar{bar: s.Bar.bar(), trades: s.Trades})
+	}
+
+	return bars
+}
+
+// indexTrade returns the index of the trade with the ID, or -1 if there is none.
+func indexTrade(trades []Trade, id string) int {
+	if id == "" {
+		return -1
+	}
+
+	return slices.IndexFunc(trades, func(t Trade) bool {
+		return t.ID == id
+	})
+}
+
+// insertTrade inserts the trade into trades ordered by time, after the trades at the same time.
+func insertTrade(trades []Trade, t Trade) []Trade {
+	i := sort.Search(len(trades), func(i int) bool {
+		return trades[i].Time.After(t.Time)
+	})
+
+	return slices.Insert(trades, i, t)
+}
+
+// rebuildBar returns a new bar with the symbol, start, end, revision and close metadata of bar, built from the trades
+// in order. The new bar has features if bar has. Without trades, the new bar has zero prices for the caller to fill.
+func rebuildBar(bar *Bar, trades []Trade) *Bar {
+	rebuilt := &Bar{
+		Symbol:      bar.Symbol,
+		Start:       bar.Start,
+		End:         bar.End,
+		Revision:    bar.Revision,
+		Processor:   bar.Processor,
+		Sequence:    bar.Sequence,
+		CloseReason: bar.CloseReason,
+	}
+
+	if bar.features != nil {
+		rebuilt.features = &featureAccumulator{}
+	}
+
+	for _, t := range trades {
+		rebuilt.applyTrade(t)
+	}
+
+	return rebuilt
+}
@@ -0,0 +1,73 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender
+
+import (
+	"time"
+)
+
+// SessionBoundary describes when a trading day rolls over. Processors close the bar in progress and reset their
+// accumulators when a trade falls in a later trading day than the start of the current bar.
+//
+// The zero value rolls over at midnight UTC.
+type SessionBoundary struct {
+	// Rollover is the time of day, in Location, at which a new trading day starts.
+	Rollover time.Duration
+	// Location of the rollover time, defaults to UTC.
+	Location *time.Location
+	// Disabled turns off session boundaries, letting bars span trading days.
+	Disabled bool
+}
+
+// Day returns the start of the trading day containing t.
+func (s SessionBoundary) Day(t time.Time) time.Time {
+	return s.rollover().anchor(t)
+}
+
+// next returns the start of the trading day after the one containing t.
+func (s SessionBoundary) next(t time.Time) time.Time {
+	return s.rollover().next(t)
+}
+
+// rollover returns the alignment anchored to the rollover time.
+func (s SessionBoundary) rollover() alignment {
+	location := s.Location
+	if location == nil {
+		location = time.UTC
+	}
+
+	return alignment{location: location, offset: s.Rollover}
+}
+
+// crossed reports whether next falls in a different trading day than start.
+func (s SessionBoundary) crossed(start, next time.Time) bool {
+	if s.Disabled || start.IsZero() {
+		return false
+	}
+
+	return !s.Day(start).Equal(s.Day(next))
+}
+
+// WithSessionBoundary sets the time of day, in the given location, at which the processor's trading day rolls over.
+// The processor type must be given explicitly, e.g. WithSessionBoundary[DollarBarConfig](17*time.Hour, chicago).
+func WithSessionBoundary[T any, PT configurable[T]](rollover time.Duration, location *time.Location) Option[T] {
+	return func(c *T) {
+		PT(c).setSessionBoundary(SessionBoundary{Rollover: rollover, Location: location})
+	}
+}
+
+// WithoutSessionBoundary lets the processor's bars span trading days.
+func WithoutSessionBoundary[T any, PT configurable[T]]() Option[T] {
+	return func(c *T) {
+		PT(c).setSessionBoundary(SessionBoundary{Disabled: true})
+	}
+}
+
+func (c *processorConfig) setSessionBoundary(session SessionBoundary) {
+	c.session = session
+}
@@ -0,0 +1,159 @@
+/*
+Copyright © 2025 Chris Griffis <dev@chrisgriffis.com> and contributors.
+
+All rights reserved.
+Licensed under the MIT license. See LICENSE file in the project root for details.
+*/
+
+package bartender_test
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	decimal "github.com/alpacahq/alpacadecimal"
+	"github.com/csgriffis/bartender"
+)
+
+func TestSessionBoundary_Day(t *testing.T) {
+	chicago, err := time.LoadLocation("America/Chicago")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+
+	tt := []struct {
+		name     string
+		boundary bartender.SessionBoundary
+		t        time.Time
+		want     time.Time
+	}{
+		{
+			name: "Midnight UTC",
+			t:    time.Date(2025, 1, 2, 23, 59, 0, 0, time.UTC),
+			want: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Before Rollover",
+			boundary: bartender.SessionBoundary{Rollover: 17 * time.Hour, Location: chicago},
+			t:        time.Date(2025, 1, 2, 16, 59, 0, 0, chicago),
+			want:     time.Date(2025, 1, 1, 17, 0, 0, 0, chicago),
+		},
+		{
+			name:     "At Rollover",
+			boundary: bartender.SessionBoundary{Rollover: 17 * time.Hour, Location: chicago},
+			t:        time.Date(2025, 1, 2, 23, 0, 0, 0, time.UTC),
+			want:     time.Date(2025, 1, 2, 17, 0, 0, 0, chicago),
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.boundary.Day(tc.t); !got.Equal(tc.want) {
+				t.Errorf("Day() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWithSessionBoundary(t *testing.T) {
+	chicago, err := time.LoadLocation("America/Chicago")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+
+	bar := func(price int64, start time.Time) bartender.Bar {
+		return bartender.Bar{
+			Open:      decimal.NewFromInt(price),
+			High:      decimal.NewFromInt(price),
+			Low:       decimal.NewFromInt(price),
+			Close:     decimal.NewFromInt(price),
+			Volume:    decimal.NewFromInt(1),
+			Start:     start,
+			BuyVolume: decimal.NewFromInt(1),
+			Ticks:     1,
+		}
+	}
+
+	merged := func(start time.Time) bartender.Bar {
+		b := bar(100, start)
+		b.High = decimal.NewFromInt(101)
+		b.Close = decimal.NewFromInt(101)
+		b.Volume = decimal.NewFromInt(2)
+		b.BuyVolume = decimal.NewFromInt(2)
+		b.Ticks = 2
+		b.Upticks = 1
+
+		return b
+	}
+
+	tt := []TestCase[bartender.Option[bartender.TickBarConfig]]{
+		{
+			name: "Default Rollover at Midnight UTC",
+			trades: []bartender.Trade{
+				{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 23, 59, 0, 0, time.UTC)},
+				{Price: decimal.NewFromInt(101), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 2, 0, 1, 0, 0, time.UTC)},
+			},
+			want: []bartender.Bar{
+				bar(100, time.Date(2025, 1, 1, 23, 59, 0, 0, time.UTC)),
+				bar(101, time.Date(2025, 1, 2, 0, 1, 0, 0, time.UTC)),
+			},
+		},
+		{
+			name: "Same Weekday One Week Apart",
+			trades: []bartender.Trade{
+				{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)},
+				{Price: decimal.NewFromInt(101), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 8, 10, 0, 0, 0, time.UTC)},
+			},
+			want: []bartender.Bar{
+				bar(100, time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)),
+				bar(101, time.Date(2025, 1, 8, 10, 0, 0, 0, time.UTC)),
+			},
+		},
+		{
+			name:  "Custom Rollover",
+			input: bartender.WithSessionBoundary[bartender.TickBarConfig](17*time.Hour, chicago),
+			trades: []bartender.Trade{
+				{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 22, 59, 0, 0, time.UTC)},
+				{Price: decimal.NewFromInt(101), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 23, 1, 0, 0, time.UTC)},
+			},
+			want: []bartender.Bar{
+				bar(100, time.Date(2025, 1, 1, 22, 59, 0, 0, time.UTC)),
+				bar(101, time.Date(2025, 1, 1, 23, 1, 0, 0, time.UTC)),
+			},
+		},
+		{
+			name:  "Custom Rollover Spans Midnight UTC",
+			input: bartender.WithSessionBoundary[bartender.TickBarConfig](17*time.Hour, chicago),
+			trades: []bartender.Trade{
+				{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 23, 30, 0, 0, time.UTC)},
+				{Price: decimal.NewFromInt(101), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 2, 0, 30, 0, 0, time.UTC)},
+			},
+			want: []bartender.Bar{merged(time.Date(2025, 1, 1, 23, 30, 0, 0, time.UTC))},
+		},
+		{
+			name:  "Disabled",
+			input: bartender.WithoutSessionBoundary[bartender.TickBarConfig](),
+			trades: []bartender.Trade{
+				{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 22, 59, 0, 0, time.UTC)},
+				{Price: decimal.NewFromInt(101), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 3, 10, 0, 0, 0, time.UTC)},
+			},
+			want: []bartender.Bar{merged(time.Date(2025, 1, 1, 22, 59, 0, 0, time.UTC))},
+		},
+	}
+
+	for _, tc := range tt {
+		options := []bartender.Option[bartender.TickBarConfig]{bartender.WithTickThreshold(10)}
+		if tc.input != nil {
+			options = append(options, tc.input)
+		}
+
+		p, err := bartender.New(options...)
+		if err != nil {
+			t.Errorf("New() error = %v", err)
+			return
+		}
+
+		tc.Run(t, p)
+	}
+}
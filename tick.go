@@ -8,6 +8,8 @@ Licensed under the MIT license. See LICENSE file in the project root for details
 package bartender
 
 import (
+	"context"
+
 	decimal "github.com/alpacahq/alpacadecimal"
 )
 
@@ -18,10 +20,16 @@ func WithTickThreshold(threshold int64) Option[TickBarConfig] {
 }
 
 type TickBarConfig struct {
+	processorConfig
+
 	tickThreshold decimal.Decimal `validate:"required"`
 }
 
 func (c TickBarConfig) Process(trades <-chan Trade) chan *Bar {
+	return c.ProcessContext(context.Background(), trades)
+}
+
+func (c TickBarConfig) ProcessContext(ctx context.Context, trades <-chan Trade) chan *Bar {
 	output := make(chan *Bar)
 
 	go func() {
@@ -30,39 +38,57 @@ func (c TickBarConfig) Process(trades <-chan Trade) chan *Bar {
 		var current *Bar
 		var tradeCount decimal.Decimal
 
-		for trade := range trades {
+		// resume from the checkpoint, if any
+		var state accumulatorState
+		if c.restore(&state) {
+			current, tradeCount = state.Current.bar(), state.Value
+		}
+
+		partial := c.partials()
+
+		for trade := range c.newTrades(ctx, trades) {
 			// initialize the current bar if it doesn't exist
 			if current == nil {
 				current = &Bar{}
 			}
 
-			// check if the trade is on a new day
-			if !current.Start.IsZero() && current.Start.Weekday() != trade.Time.Weekday() {
+			// check if the trade is in a new session
+			if c.session.crossed(current.Start, trade.Time) {
 				finalizedBar := current
 
-				output <- finalizedBar
+				if !c.closeBar(ctx, output, finalizedBar, CloseReasonSession) {
+					return
+				}
 
 				// reset the current bar
 				current = &Bar{}
 				tradeCount = decimal.Zero
 			}
 
-			current.applyTrade(trade)
+			c.apply(current, trade)
 
 			// increment counter
 			tradeCount = tradeCount.Add(decimal.NewFromInt(1))
 
 			if tradeCount.GreaterThanOrEqual(c.tickThreshold) {
 				finalizedBar := current
-				output <- finalizedBar
+				if !c.closeBar(ctx, output, finalizedBar, CloseReasonThreshold) {
+					return
+				}
 
 				current = nil
 				tradeCount = decimal.Zero
 			}
+
+			if !partial.publish(ctx, output, trade, current) {
+				return
+			}
+
+			c.save(trade, accumulatorState{Current: snapshotBar(current), Value: tradeCount})
 		}
 
 		if current != nil {
-			output <- current
+			c.closeBar(ctx, output, current, CloseReasonEndOfStream)
 		}
 	}()
 
@@ -76,10 +102,16 @@ func WithTickImbalanceThreshold(threshold int64) Option[TickImbalanceBarConfig]
 }
 
 type TickImbalanceBarConfig struct {
+	processorConfig
+
 	imbalanceThreshold decimal.Decimal `validate:"required"`
 }
 
 func (c TickImbalanceBarConfig) Process(trades <-chan Trade) chan *Bar {
+	return c.ProcessContext(context.Background(), trades)
+}
+
+func (c TickImbalanceBarConfig) ProcessContext(ctx context.Context, trades <-chan Trade) chan *Bar {
 	output := make(chan *Bar)
 
 	go func() {
@@ -89,7 +121,15 @@ func (c TickImbalanceBarConfig) Process(trades <-chan Trade) chan *Bar {
 		var netImbalance decimal.Decimal
 		var prevPrice decimal.Decimal
 
-		for trade := range trades {
+		// resume from the checkpoint, if any
+		var state accumulatorState
+		if c.restore(&state) {
+			current, netImbalance, prevPrice = state.Current.bar(), state.Value, state.PrevPrice
+		}
+
+		partial := c.partials()
+
+		for trade := range c.newTrades(ctx, trades) {
 			// initialize the current bar if it doesn't exist
 			if current == nil {
 				current = &Bar{}
@@ -107,18 +147,20 @@ func (c TickImbalanceBarConfig) Process(trades <-chan Trade) chan *Bar {
 				}
 			}
 
-			// check if the trade is on a new day
-			if !current.Start.IsZero() && current.Start.Weekday() != trade.Time.Weekday() {
+			// check if the trade is in a new session
+			if c.session.crossed(current.Start, trade.Time) {
 				finalizedBar := current
 
-				output <- finalizedBar
+				if !c.closeBar(ctx, output, finalizedBar, CloseReasonSession) {
+					return
+				}
 
 				// reset the current bar
 				current = &Bar{}
 				netImbalance = decimal.Zero
 			}
 
-			current.applyTrade(trade)
+			c.apply(current, trade)
 
 			// update net imbalance
 			if trade.Price.GreaterThan(prevPrice) {
@@ -131,15 +173,23 @@ func (c TickImbalanceBarConfig) Process(trades <-chan Trade) chan *Bar {
 
 			if netImbalance.Abs().GreaterThanOrEqual(c.imbalanceThreshold) {
 				finalizedBar := current
-				output <- finalizedBar
+				if !c.closeBar(ctx, output, finalizedBar, CloseReasonThreshold) {
+					return
+				}
 
 				current = nil
 				netImbalance = decimal.Zero
 			}
+
+			if !partial.publish(ctx, output, trade, current) {
+				return
+			}
+
+			c.save(trade, accumulatorState{Current: snapshotBar(current), Value: netImbalance, PrevPrice: prevPrice})
 		}
 
 		if current != nil {
-			output <- current
+			c.closeBar(ctx, output, current, CloseReasonEndOfStream)
 		}
 	}()
 
@@ -153,10 +203,16 @@ func WithTickRunThreshold(threshold int64) Option[TickRunsBarConfig] {
 }
 
 type TickRunsBarConfig struct {
+	processorConfig
+
 	runsLengthThreshold decimal.Decimal `validate:"required"`
 }
 
 func (c TickRunsBarConfig) Process(trades <-chan Trade) chan *Bar {
+	return c.ProcessContext(context.Background(), trades)
+}
+
+func (c TickRunsBarConfig) ProcessContext(ctx context.Context, trades <-chan Trade) chan *Bar {
 	output := make(chan *Bar)
 
 	go func() {
@@ -166,7 +222,15 @@ func (c TickRunsBarConfig) Process(trades <-chan Trade) chan *Bar {
 		var upwardRun, downwardRun decimal.Decimal
 		var prevPrice decimal.Decimal
 
-		for trade := range trades {
+		// resume from the checkpoint, if any
+		var state runState
+		if c.restore(&state) {
+			current, upwardRun, downwardRun, prevPrice = state.Current.bar(), state.Up, state.Down, state.PrevPrice
+		}
+
+		partial := c.partials()
+
+		for trade := range c.newTrades(ctx, trades) {
 			// initialize the current bar if it doesn't exist
 			if current == nil {
 				current = &Bar{}
@@ -177,11 +241,13 @@ func (c TickRunsBarConfig) Process(trades <-chan Trade) chan *Bar {
 				prevPrice = trade.Price
 			}
 
-			// check if the trade is on a new day
-			if !current.Start.IsZero() && current.Start.Weekday() != trade.Time.Weekday() {
+			// check if the trade is in a new session
+			if c.session.crossed(current.Start, trade.Time) {
 				finalizedBar := current
 
-				output <- finalizedBar
+				if !c.closeBar(ctx, output, finalizedBar, CloseReasonSession) {
+					return
+				}
 
 				// reset the current bar
 				current = &Bar{}
@@ -189,7 +255,7 @@ func (c TickRunsBarConfig) Process(trades <-chan Trade) chan *Bar {
 				downwardRun = decimal.Zero
 			}
 
-			current.applyTrade(trade)
+			c.apply(current, trade)
 
 			// determine the direction of the tick and update runs
 			if trade.Price.GreaterThan(prevPrice) {
@@ -205,23 +271,100 @@ func (c TickRunsBarConfig) Process(trades <-chan Trade) chan *Bar {
 			// check if a new bar should be created based on the run threshold
 			if upwardRun.GreaterThanOrEqual(c.runsLengthThreshold) || downwardRun.GreaterThanOrEqual(c.runsLengthThreshold) {
 				finalizedBar := current
-				output <- finalizedBar
+				if !c.closeBar(ctx, output, finalizedBar, CloseReasonThreshold) {
+					return
+				}
 
 				current = nil
 				upwardRun = decimal.Zero
 				downwardRun = decimal.Zero
 			}
+
+			if !partial.publish(ctx, output, trade, current) {
+				return
+			}
+
+			c.save(trade, runState{Current: snapshotBar(current), Up: upwardRun, Down: downwardRun, PrevPrice: prevPrice})
 		}
 
 		if current != nil {
-			output <- current
+			c.closeBar(ctx, output, current, CloseReasonEndOfStream)
 		}
 	}()
 
 	return output
 }
 
+// WithAdaptiveTickImbalance configures a tick imbalance bar whose threshold adapts to previous bars. warmup is the
+// expected number of ticks per bar until the first bar closes and span the span of the moving averages.
+func WithAdaptiveTickImbalance(warmup, span int64) Option[AdaptiveTickImbalanceBarConfig] {
+	return func(c *AdaptiveTickImbalanceBarConfig) {
+		c.warmup = warmup
+		c.span = span
+	}
+}
+
+// WithAdaptiveTickImbalanceLimits clamps the expected number of ticks per bar.
+func WithAdaptiveTickImbalanceLimits(minTicks, maxTicks int64) Option[AdaptiveTickImbalanceBarConfig] {
+	return func(c *AdaptiveTickImbalanceBarConfig) {
+		c.minTicks = minTicks
+		c.maxTicks = maxTicks
+	}
+}
+
+// AdaptiveTickImbalanceBarConfig closes a bar once the signed tick imbalance exceeds the expected imbalance, estimated
+// from previous bars.
+type AdaptiveTickImbalanceBarConfig struct {
+	adaptiveConfig
+}
+
+func (c AdaptiveTickImbalanceBarConfig) Process(trades <-chan Trade) chan *Bar {
+	return c.ProcessContext(context.Background(), trades)
+}
+
+func (c AdaptiveTickImbalanceBarConfig) ProcessContext(ctx context.Context, trades <-chan Trade) chan *Bar {
+	return c.processImbalance(ctx, trades, tickMeasure)
+}
+
+// WithAdaptiveTickRun configures a tick run bar whose threshold adapts to previous bars. warmup is the expected number
+// of ticks per bar until the first bar closes and span the span of the moving averages.
+func WithAdaptiveTickRun(warmup, span int64) Option[AdaptiveTickRunBarConfig] {
+	return func(c *AdaptiveTickRunBarConfig) {
+		c.warmup = warmup
+		c.span = span
+	}
+}
+
+// WithAdaptiveTickRunLimits clamps the expected number of ticks per bar.
+func WithAdaptiveTickRunLimits(minTicks, maxTicks int64) Option[AdaptiveTickRunBarConfig] {
+	return func(c *AdaptiveTickRunBarConfig) {
+		c.minTicks = minTicks
+		c.maxTicks = maxTicks
+	}
+}
+
+// AdaptiveTickRunBarConfig closes a bar once the larger of the buy and sell ticks in the bar exceeds the expected
+// run, estimated from previous bars.
+type AdaptiveTickRunBarConfig struct {
+	adaptiveConfig
+}
+
+func (c AdaptiveTickRunBarConfig) Process(trades <-chan Trade) chan *Bar {
+	return c.ProcessContext(context.Background(), trades)
+}
+
+func (c AdaptiveTickRunBarConfig) ProcessContext(ctx context.Context, trades <-chan Trade) chan *Bar {
+	return c.processRuns(ctx, trades, tickMeasure)
+}
+
 // Interface guards
 var _ Processor = (*TickBarConfig)(nil)
 var _ Processor = (*TickImbalanceBarConfig)(nil)
 var _ Processor = (*TickRunsBarConfig)(nil)
+var _ Processor = (*AdaptiveTickImbalanceBarConfig)(nil)
+var _ Processor = (*AdaptiveTickRunBarConfig)(nil)
+var _ ContextProcessor = (*TickBarConfig)(nil)
+var _ ContextProcessor = (*TickImbalanceBarConfig)(nil)
+var _ ContextProcessor = (*TickRunsBarConfig)(nil)
+var _ ContextProcessor = (*AdaptiveTickImbalanceBarConfig)(nil)
+var _ ContextProcessor = (*AdaptiveTickRunBarConfig)(nil)
@@ -80,6 +80,29 @@ func TestTickThresholdBarConfig_Process(t *testing.T) {
 			trades: []bartender.Trade{},
 			want:   []bartender.Bar{},
 		},
+		{
+			name:  "Ignores Cancels and Corrections",
+			input: 2,
+			trades: []bartender.Trade{
+				{ID: "1", Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)},
+				{ID: "1", Action: bartender.ActionCancel, Time: time.Date(2025, 1, 1, 10, 0, 10, 0, time.UTC)},
+				{ID: "1", Action: bartender.ActionCorrect, Price: decimal.NewFromInt(90), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)},
+				{ID: "2", Price: decimal.NewFromInt(101), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 30, 0, time.UTC)},
+			},
+			want: []bartender.Bar{
+				{
+					Open:      decimal.NewFromInt(100),
+					High:      decimal.NewFromInt(101),
+					Low:       decimal.NewFromInt(100),
+					Close:     decimal.NewFromInt(101),
+					Volume:    decimal.NewFromInt(2),
+					Start:     time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+					BuyVolume: decimal.NewFromInt(2),
+					Ticks:     2,
+					Upticks:   1,
+				},
+			},
+		},
 	}
 
 	for _, tc := range tt {
@@ -213,3 +236,199 @@ func TestTickRunBarConfig_Process(t *testing.T) {
 		tc.Run(t, p)
 	}
 }
+
+func TestAdaptiveTickImbalanceBarConfig_Process(t *testing.T) {
+	type adaptiveInput struct {
+		warmup, span, minTicks, maxTicks int64
+	}
+
+	tt := []TestCase[adaptiveInput]{
+		{
+			name:  "Expected Imbalance Trigger",
+			input: adaptiveInput{warmup: 4, span: 3},
+			trades: []bartender.Trade{
+				{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)},
+				{Price: decimal.NewFromInt(101), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 10, 0, time.UTC)},
+				{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 20, 0, time.UTC)},
+				{Price: decimal.NewFromInt(101), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 30, 0, time.UTC)},
+				{Price: decimal.NewFromInt(102), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 40, 0, time.UTC)},
+				{Price: decimal.NewFromInt(103), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 50, 0, time.UTC)},
+				{Price: decimal.NewFromInt(104), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 55, 0, time.UTC)},
+			},
+			want: []bartender.Bar{
+				{
+					Open:      decimal.NewFromInt(100),
+					High:      decimal.NewFromInt(101),
+					Low:       decimal.NewFromInt(100),
+					Close:     decimal.NewFromInt(101),
+					Volume:    decimal.NewFromInt(4),
+					Start:     time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+					BuyVolume: decimal.NewFromInt(4),
+					Ticks:     4,
+					Upticks:   2,
+					Threshold: decimal.NewFromFloat(2),
+				},
+				{
+					Open:      decimal.NewFromInt(102),
+					High:      decimal.NewFromInt(104),
+					Low:       decimal.NewFromInt(102),
+					Close:     decimal.NewFromInt(104),
+					Volume:    decimal.NewFromInt(3),
+					Start:     time.Date(2025, 1, 1, 10, 0, 40, 0, time.UTC),
+					BuyVolume: decimal.NewFromInt(3),
+					Ticks:     3,
+					Upticks:   2,
+					Threshold: decimal.NewFromFloat(3.75),
+				},
+			},
+		},
+		{
+			name:  "Clamped Expected Ticks",
+			input: adaptiveInput{warmup: 2, span: 3, minTicks: 3},
+			trades: []bartender.Trade{
+				{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)},
+				{Price: decimal.NewFromInt(101), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 10, 0, time.UTC)},
+				{Price: decimal.NewFromInt(102), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 20, 0, time.UTC)},
+				{Price: decimal.NewFromInt(103), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 30, 0, time.UTC)},
+				{Price: decimal.NewFromInt(104), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 40, 0, time.UTC)},
+				{Price: decimal.NewFromInt(105), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 50, 0, time.UTC)},
+				{Price: decimal.NewFromInt(106), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 55, 0, time.UTC)},
+			},
+			want: []bartender.Bar{
+				{
+					Open:      decimal.NewFromInt(100),
+					High:      decimal.NewFromInt(102),
+					Low:       decimal.NewFromInt(100),
+					Close:     decimal.NewFromInt(102),
+					Volume:    decimal.NewFromInt(3),
+					Start:     time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+					BuyVolume: decimal.NewFromInt(3),
+					Ticks:     3,
+					Upticks:   2,
+					Threshold: decimal.NewFromFloat(3),
+				},
+				{
+					Open:      decimal.NewFromInt(103),
+					High:      decimal.NewFromInt(105),
+					Low:       decimal.NewFromInt(103),
+					Close:     decimal.NewFromInt(105),
+					Volume:    decimal.NewFromInt(3),
+					Start:     time.Date(2025, 1, 1, 10, 0, 30, 0, time.UTC),
+					BuyVolume: decimal.NewFromInt(3),
+					Ticks:     3,
+					Upticks:   2,
+					Threshold: decimal.NewFromFloat(3),
+				},
+				{
+					Open:      decimal.NewFromInt(106),
+					High:      decimal.NewFromInt(106),
+					Low:       decimal.NewFromInt(106),
+					Close:     decimal.NewFromInt(106),
+					Volume:    decimal.NewFromInt(1),
+					Start:     time.Date(2025, 1, 1, 10, 0, 55, 0, time.UTC),
+					BuyVolume: decimal.NewFromInt(1),
+					Ticks:     1,
+					Threshold: decimal.NewFromFloat(3),
+				},
+			},
+		},
+		{
+			name:   "No Trades",
+			input:  adaptiveInput{warmup: 2},
+			trades: []bartender.Trade{},
+			want:   []bartender.Bar{},
+		},
+	}
+
+	for _, tc := range tt {
+		p, err := bartender.New(
+			bartender.WithAdaptiveTickImbalance(tc.input.warmup, tc.input.span),
+			bartender.WithAdaptiveTickImbalanceLimits(tc.input.minTicks, tc.input.maxTicks),
+		)
+		if err != nil {
+			t.Errorf("New() error = %v", err)
+			return
+		}
+
+		tc.Run(t, p)
+	}
+}
+
+func TestAdaptiveTickRunBarConfig_Process(t *testing.T) {
+	type adaptiveInput struct {
+		warmup, span, minTicks, maxTicks int64
+	}
+
+	tt := []TestCase[adaptiveInput]{
+		{
+			name:  "Expected Run Trigger",
+			input: adaptiveInput{warmup: 4, span: 3},
+			trades: []bartender.Trade{
+				{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)},
+				{Price: decimal.NewFromInt(101), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 10, 0, time.UTC)},
+				{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideSell, Time: time.Date(2025, 1, 1, 10, 0, 20, 0, time.UTC)},
+				{Price: decimal.NewFromInt(99), Size: decimal.NewFromInt(1), Side: bartender.SideSell, Time: time.Date(2025, 1, 1, 10, 0, 30, 0, time.UTC)},
+				{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 40, 0, time.UTC)},
+				{Price: decimal.NewFromInt(101), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 50, 0, time.UTC)},
+				{Price: decimal.NewFromInt(102), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 55, 0, time.UTC)},
+			},
+			want: []bartender.Bar{
+				{
+					Open:       decimal.NewFromInt(100),
+					High:       decimal.NewFromInt(101),
+					Low:        decimal.NewFromInt(99),
+					Close:      decimal.NewFromInt(99),
+					Volume:     decimal.NewFromInt(4),
+					Start:      time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+					BuyVolume:  decimal.NewFromInt(2),
+					SellVolume: decimal.NewFromInt(2),
+					Ticks:      4,
+					Upticks:    1,
+					Threshold:  decimal.NewFromFloat(2),
+				},
+				{
+					Open:      decimal.NewFromInt(100),
+					High:      decimal.NewFromInt(101),
+					Low:       decimal.NewFromInt(100),
+					Close:     decimal.NewFromInt(101),
+					Volume:    decimal.NewFromInt(2),
+					Start:     time.Date(2025, 1, 1, 10, 0, 40, 0, time.UTC),
+					BuyVolume: decimal.NewFromInt(2),
+					Ticks:     2,
+					Upticks:   1,
+					Threshold: decimal.NewFromFloat(2),
+				},
+				{
+					Open:      decimal.NewFromInt(102),
+					High:      decimal.NewFromInt(102),
+					Low:       decimal.NewFromInt(102),
+					Close:     decimal.NewFromInt(102),
+					Volume:    decimal.NewFromInt(1),
+					Start:     time.Date(2025, 1, 1, 10, 0, 55, 0, time.UTC),
+					BuyVolume: decimal.NewFromInt(1),
+					Ticks:     1,
+					Threshold: decimal.NewFromFloat(2.25),
+				},
+			},
+		},
+		{
+			name:   "No Trades",
+			input:  adaptiveInput{warmup: 2},
+			trades: []bartender.Trade{},
+			want:   []bartender.Bar{},
+		},
+	}
+
+	for _, tc := range tt {
+		p, err := bartender.New(
+			bartender.WithAdaptiveTickRun(tc.input.warmup, tc.input.span),
+			bartender.WithAdaptiveTickRunLimits(tc.input.minTicks, tc.input.maxTicks),
+		)
+		if err != nil {
+			t.Errorf("New() error = %v", err)
+			return
+		}
+
+		tc.Run(t, p)
+	}
+}
@@ -8,7 +8,12 @@ Licensed under the MIT license. See LICENSE file in the project root for details
 package bartender
 
 import (
+	"context"
+	"fmt"
+	"slices"
 	"time"
+
+	decimal "github.com/alpacahq/alpacadecimal"
 )
 
 func WithInterval(interval time.Duration) Option[TimeBarConfig] {
@@ -17,43 +22,301 @@ func WithInterval(interval time.Duration) Option[TimeBarConfig] {
 	}
 }
 
+// WithAlignment anchors intervals to a time of day in the given location instead of the Unix epoch in UTC, for
+// example WithAlignment(newYork, 9*time.Hour+30*time.Minute) starts intervals at 09:30 New York time each day. The
+// interval should divide the day evenly, otherwise the last interval of each day is cut short by the next anchor.
+func WithAlignment(location *time.Location, offset time.Duration) Option[TimeBarConfig] {
+	return func(v *TimeBarConfig) {
+		v.alignment = alignment{location: location, offset: offset}
+	}
+}
+
+// WithCalendar skips the empty bars that fill gaps between trades for intervals that start outside the calendar's
+// sessions of the given kinds, or of any kind when none are given, such as nights, weekends and holidays.
+func WithCalendar(calendar *Calendar, kinds ...SessionKind) Option[TimeBarConfig] {
+	return func(v *TimeBarConfig) {
+		v.calendar = calendar
+		v.sessionKinds = kinds
+	}
+}
+
+// WithClock closes bars once the clock passes the end of their interval plus delay, instead of waiting for the first
+// trade of a later interval, and emits the empty bars of intervals without trades as they end. The delay allows for
+// trades that arrive late; trades arriving after their interval has closed are added to the bar in progress. The
+// clock must keep the same time as the trade timestamps.
+func WithClock(clock Clock, delay time.Duration) Option[TimeBarConfig] {
+	return func(v *TimeBarConfig) {
+		v.clock = clock
+		v.delay = delay
+	}
+}
+
+// WithRevisions keeps the trades of closed bars until horizon after their interval has ended. A trade that arrives
+// late for one of them, for instance from a Reordered processor with the CorrectLate policy, emits a revised bar with
+// the trade included and Revision incremented, as do cancels and corrections of the trades in them. Trades arriving
+// out of order for the bar in progress are put back in time order, so its Open and Close are those of its earliest
+// and latest trades. Without revisions, cancels and corrections are ignored.
+func WithRevisions(horizon time.Duration) Option[TimeBarConfig] {
+	return func(v *TimeBarConfig) {
+		v.horizon = horizon
+	}
+}
+
 type TimeBarConfig struct {
-	interval time.Duration `validate:"required"`
+	processorConfig
+
+	interval     time.Duration `validate:"required"`
+	alignment    alignment
+	calendar     *Calendar
+	sessionKinds []SessionKind
+	clock        Clock
+	delay        time.Duration
+	horizon      time.Duration
 }
 
 func (c TimeBarConfig) Process(trades <-chan Trade) chan *Bar {
+	return c.ProcessContext(context.Background(), trades)
+}
+
+func (c TimeBarConfig) ProcessContext(ctx context.Context, trades <-chan Trade) chan *Bar {
 	output := make(chan *Bar)
 
 	go func() {
 		defer close(output)
 
 		var current *Bar
-		for trade := range trades {
-			alignedStart := calculateAlignedStart(trade.Time, c.interval)
+		// trades of the current bar and recently closed bars, kept for revisions
+		var currentTrades []Trade
+		var closed []revisableBar
+		var latest time.Time
+		// close of the last bar past the revision horizon
+		var settled decimal.Decimal
+
+		snapshot := func() timeBarState {
+			return timeBarState{
+				Current: snapshotBar(current),
+				Trades:  currentTrades,
+				Closed:  snapshotRevisable(closed),
+				Latest:  latest,
+				Settled: settled,
+			}
+		}
+
+		// resume from the checkpoint, if any
+		var state timeBarState
+		if c.restore(&state) {
+			current, currentTrades = state.Current.bar(), state.Trades
+			closed, latest, settled = restoreRevisable(state.Closed), state.Latest, state.Settled
+		}
+
+		partial := c.partials()
+
+		// gap returns the empty bar for the interval after bar
+		gap := func(bar *Bar) *Bar {
+			return &Bar{
+				Symbol: bar.Symbol,
+				Open:   bar.Close,
+				High:   bar.Close,
+				Low:    bar.Close,
+				Close:  bar.Close,
+				Start:  c.end(bar.Start),
+			}
+		}
+
+		// emit closes a bar ending with its interval, skipping empty bars for intervals the market is closed for
+		emit := func(bar *Bar, reason CloseReason) bool {
+			if bar.Ticks == 0 && c.calendar != nil && !c.calendar.IsOpen(bar.Start, c.sessionKinds...) {
+				return true
+			}
+
+			bar.End = c.end(bar.Start)
+
+			return c.closeBar(ctx, output, bar, reason)
+		}
+
+		// finalize emits the current bar and keeps it for revisions until the horizon has passed
+		finalize := func(reason CloseReason) bool {
+			if !emit(current, reason) {
+				return false
+			}
+
+			if c.horizon > 0 {
+				closed = append(closed, revisableBar{bar: current, trades: currentTrades})
+			}
+
+			currentTrades = nil
+
+			return true
+		}
+
+		// fill sets the prices of a bar left without trades to the close of the bar before it, as for gap bars. A bar
+		// without a bar before it has no price to carry and keeps zero prices.
+		fill := func(bar *Bar, before []revisableBar) {
+			if bar.Ticks > 0 {
+				return
+			}
+
+			prevClose := settled
+			if len(before) > 0 {
+				prevClose = before[len(before)-1].bar.Close
+			}
+
+			bar.Open, bar.High, bar.Low, bar.Close = prevClose, prevClose, prevClose, prevClose
+		}
+
+		// amend applies a cancel or correction to the bar holding the trade it refers to, emitting a revised bar if
+		// that bar has already been emitted. A correction that moves the trade out of the bar's interval is returned
+		// to be processed as a new trade.
+		amend := func(event Trade) (*Trade, bool) {
+			corrected := event
+			corrected.Action = ""
+
+			// replace removes the trade at i and adds the correction if it stays in the interval starting at start
+			replace := func(trades []Trade, i int, start time.Time) ([]Trade, *Trade) {
+				trades = slices.Delete(trades, i, i+1)
+
+				if event.Action != ActionCorrect {
+					return trades, nil
+				}
+
+				if corrected.Time.Before(start) || !corrected.Time.Before(c.end(start)) {
+					return trades, &corrected
+				}
+
+				return insertTrade(trades, corrected), nil
+			}
+
+			if i := indexTrade(currentTrades, event.ID); current != nil && i >= 0 {
+				var moved *Trade
+				currentTrades, moved = replace(currentTrades, i, current.Start)
+				current = rebuildBar(current, currentTrades)
+				fill(current, closed)
+
+				return moved, true
+			}
+
+			for j := range closed {
+				if i := indexTrade(closed[j].trades, event.ID); i >= 0 {
+					var moved *Trade
+					closed[j].trades, moved = replace(closed[j].trades, i, closed[j].bar.Start)
+					closed[j].bar = rebuildBar(closed[j].bar, closed[j].trades)
+					closed[j].bar.Revision++
+					fill(closed[j].bar, closed[:j])
+
+					return moved, send(ctx, output, closed[j].bar)
+				}
+			}
+
+			// the trade is unknown or past the revision horizon
+			return nil, true
+		}
+
+		// end of the interval the clock is timing
+		var expiry time.Time
+		var expired <-chan time.Time
+
+		for {
+			// time the end of the current interval
+			if c.clock != nil && current != nil && !c.end(current.Start).Equal(expiry) {
+				expiry = c.end(current.Start)
+				expired = c.clock.After(expiry.Add(c.delay).Sub(c.clock.Now()))
+			}
+
+			var trade Trade
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-expired:
+				expired = nil
+
+				// the interval has ended, so finalize it and leave an empty bar open for the next one
+				if !finalize(c.closeReason(current.Start)) {
+					return
+				}
+
+				current = gap(current)
+
+				c.saveState(snapshot())
+
+				continue
+			case next, ok := <-trades:
+				if !ok {
+					// send the last bar, unless it is an empty bar left open by the clock
+					if current != nil && current.Ticks > 0 {
+						emit(current, CloseReasonEndOfStream)
+					}
+
+					return
+				}
+
+				trade = next
+			}
+
+			// is the trade a cancel or correction?
+			if trade.Action != "" {
+				moved, ok := amend(trade)
+				if !ok {
+					return
+				}
+
+				if moved == nil {
+					c.save(trade, snapshot())
+					continue
+				}
+
+				// process the corrected trade in its new interval
+				trade = *moved
+			}
+
+			alignedStart := c.start(trade.Time)
 
 			// is the trade before the aligned start?
 			if trade.Time.Before(alignedStart) {
 				// then drop the trade
+				c.save(trade, snapshot())
 				continue
 			}
 
+			if trade.Time.After(latest) {
+				latest = trade.Time
+			}
+
+			// forget the closed bars past the revision horizon
+			for len(closed) > 0 && !closed[0].bar.End.Add(c.horizon).After(latest) {
+				settled = closed[0].bar.Close
+				closed = closed[1:]
+			}
+
+			// does the trade belong to a closed bar that can still be revised?
+			if current != nil && trade.Time.Before(current.Start) {
+				if i := slices.IndexFunc(closed, func(r revisableBar) bool {
+					return !trade.Time.Before(r.bar.Start) && trade.Time.Before(r.bar.End)
+				}); i >= 0 {
+					// then emit the revised bar
+					if !send(ctx, output, closed[i].revise(trade)) {
+						return
+					}
+
+					c.save(trade, snapshot())
+					continue
+				}
+			}
+
 			// is the trade beyond the current interval?
-			if current != nil && trade.Time.Sub(current.Start.Add(c.interval)).Nanoseconds() >= 0 {
+			if current != nil && !trade.Time.Before(c.end(current.Start)) {
 				// then finalize the current interval
-				output <- current
+				if !finalize(c.closeReason(current.Start)) {
+					return
+				}
 
 				// is there a gap between the current interval and the trade?
-				for nextStart := alignedStart.Add(c.interval); current.Start.Add(c.interval).Before(alignedStart); nextStart = nextStart.Add(c.interval) {
-					emptyBar := &Bar{
-						Open:  current.Close,
-						High:  current.Close,
-						Low:   current.Close,
-						Close: current.Close,
-						Start: current.Start.Add(c.interval),
-					}
-					output <- emptyBar
+				for c.end(current.Start).Before(alignedStart) {
+					current = gap(current)
 
-					current = emptyBar
+					if !finalize(c.closeReason(current.Start)) {
+						return
+					}
 				}
 
 				// start a new bar
@@ -68,6 +331,17 @@ func (c TimeBarConfig) Process(trades <-chan Trade) chan *Bar {
 				current = newBar
 			}
 
+			// is the current bar an empty bar left open by the clock?
+			if current != nil && current.Ticks == 0 {
+				// then the trade opens it
+				current = &Bar{
+					Open:  trade.Price,
+					High:  trade.Price,
+					Low:   trade.Price,
+					Start: current.Start,
+				}
+			}
+
 			if current == nil {
 				current = &Bar{
 					Open:  trade.Price,
@@ -77,35 +351,166 @@ func (c TimeBarConfig) Process(trades <-chan Trade) chan *Bar {
 				}
 			}
 
-			// check if the trade is on a new day
-			if !current.Start.IsZero() && current.Start.Weekday() != trade.Time.Weekday() {
-				finalizedBar := current
+			// check if the trade is in a new session
+			if c.session.crossed(current.Start, trade.Time) {
+				if !finalize(CloseReasonSession) {
+					return
+				}
 
-				output <- finalizedBar
+				// reset the current bar to the interval of the trade
+				current = &Bar{Start: alignedStart}
+			}
 
-				// reset the current bar
-				current = &Bar{}
+			switch {
+			case c.horizon == 0:
+				c.apply(current, trade)
+			case len(currentTrades) > 0 && trade.Time.Before(currentTrades[len(currentTrades)-1].Time):
+				// rebuild the bar with the trade in time order
+				currentTrades = insertTrade(currentTrades, trade)
+				current = rebuildBar(current, currentTrades)
+			default:
+				currentTrades = append(currentTrades, trade)
+				c.apply(current, trade)
 			}
 
-			current.applyTrade(trade)
-		}
+			if !partial.publish(ctx, output, trade, current) {
+				return
+			}
 
-		// send the last bar
-		if current != nil {
-			output <- current
+			c.save(trade, snapshot())
 		}
 	}()
 
 	return output
 }
 
+// timeBarState is the state of a TimeBarConfig processor between trades.
+type timeBarState struct {
+	Current *barState           `json:"current,omitempty"`
+	Trades  []Trade             `json:"trades,omitempty"`
+	Closed  []revisableBarState `json:"closed,omitempty"`
+	Latest  time.Time           `json:"latest"`
+	Settled decimal.Decimal     `json:"settled"`
+}
+
+// check rejects intervals that would never end.
+func (c *TimeBarConfig) check() error {
+	if c.interval <= 0 {
+		return fmt.Errorf("interval must be positive, got %s", c.interval)
+	}
+
+	return nil
+}
+
+// start returns the start of the interval containing t, which is the start of the trading day for an interval
+// straddling the session rollover.
+func (c TimeBarConfig) start(t time.Time) time.Time {
+	start := c.alignment.start(t, c.interval)
+	if c.session.Disabled {
+		return start
+	}
+
+	if day := c.session.Day(t); day.After(start) {
+		return day
+	}
+
+	return start
+}
+
+// closeReason returns why the bar starting at start closes at its end: its interval ends, or the session rolls over
+// first.
+func (c TimeBarConfig) closeReason(start time.Time) CloseReason {
+	if c.end(start).Before(c.alignment.end(start, c.interval)) {
+		return CloseReasonSession
+	}
+
+	return CloseReasonInterval
+}
+
+// end returns the end of the interval starting at start, which is cut short by the session rollover so that bars
+// never straddle trading days.
+func (c TimeBarConfig) end(start time.Time) time.Time {
+	end := c.alignment.end(start, c.interval)
+	if c.session.Disabled {
+		return end
+	}
+
+	if next := c.session.next(start); next.Before(end) {
+		return next
+	}
+
+	return end
+}
+
+// alignment anchors the start of intervals. The zero value aligns intervals to the Unix epoch in UTC.
+type alignment struct {
+	location *time.Location
+	offset   time.Duration
+}
+
+// start determines the start of the interval containing t.
+func (a alignment) start(t time.Time, interval time.Duration) time.Time {
+	if a.location == nil {
+		return calculateAlignedStart(t, interval)
+	}
+
+	anchor := a.anchor(t)
+
+	return anchor.Add(t.Sub(anchor).Truncate(interval))
+}
+
+// anchor returns the latest occurrence of the offset from midnight in the location at or before t.
+func (a alignment) anchor(t time.Time) time.Time {
+	local := t.In(a.location)
+
+	anchor := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, int(a.offset), a.location)
+	if local.Before(anchor) {
+		anchor = time.Date(local.Year(), local.Month(), local.Day()-1, 0, 0, 0, int(a.offset), a.location)
+	}
+
+	return anchor
+}
+
+// end returns the end of the interval containing start, which is cut short by the next anchor so that the following
+// interval starts on it. A start off the alignment, such as a session rollover, ends with the interval it falls in.
+func (a alignment) end(start time.Time, interval time.Duration) time.Time {
+	end := a.start(start, interval).Add(interval)
+	if a.location == nil {
+		return end
+	}
+
+	if next := a.next(start); next.Before(end) {
+		return next
+	}
+
+	return end
+}
+
+// next returns the first occurrence of the offset from midnight in the location after t.
+func (a alignment) next(t time.Time) time.Time {
+	local := t.In(a.location)
+
+	for day := 0; ; day++ {
+		next := time.Date(local.Year(), local.Month(), local.Day()+day, 0, 0, 0, int(a.offset), a.location)
+		if next.After(t) {
+			return next
+		}
+	}
+}
+
 // calculateAlignedStart determines the start time of a trade interval
 func calculateAlignedStart(t time.Time, interval time.Duration) time.Time {
-	intervalSeconds := int64(interval.Seconds())
-	timestampSeconds := t.Unix()
-	alignedSeconds := (timestampSeconds / intervalSeconds) * intervalSeconds
-	return time.Unix(alignedSeconds, 0).UTC()
+	timestamp := t.UnixNano()
+	aligned := timestamp - timestamp%int64(interval)
+
+	// round down for times before the epoch
+	if aligned > timestamp {
+		aligned -= int64(interval)
+	}
+
+	return time.Unix(0, aligned).UTC()
 }
 
 // Interface guards
 var _ Processor = (*TimeBarConfig)(nil)
+var _ ContextProcessor = (*TimeBarConfig)(nil)
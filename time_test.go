@@ -10,9 +10,12 @@ package bartender_test
 import (
 	"testing"
 	"time"
+	_ "time/tzdata"
 
 	decimal "github.com/alpacahq/alpacadecimal"
 	"github.com/csgriffis/bartender"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 )
 
 func TestTimeBarConfig_Process(t *testing.T) {
@@ -212,3 +215,563 @@ func TestTimeBarConfig_Process(t *testing.T) {
 		tc.Run(t, p)
 	}
 }
+
+func TestTimeBarConfig_SubSecond(t *testing.T) {
+	tc := TestCase[time.Duration]{
+		name:  "Millisecond Interval",
+		input: 250 * time.Millisecond,
+		trades: []bartender.Trade{
+			{Price: decimal.NewFromInt(100), Side: bartender.SideBuy, Size: decimal.NewFromInt(1), Time: time.Date(2025, 1, 1, 10, 0, 0, int(100*time.Millisecond), time.UTC)},
+			{Price: decimal.NewFromInt(101), Side: bartender.SideBuy, Size: decimal.NewFromInt(1), Time: time.Date(2025, 1, 1, 10, 0, 0, int(200*time.Millisecond), time.UTC)},
+			{Price: decimal.NewFromInt(102), Side: bartender.SideBuy, Size: decimal.NewFromInt(1), Time: time.Date(2025, 1, 1, 10, 0, 0, int(300*time.Millisecond), time.UTC)},
+		},
+		want: []bartender.Bar{
+			{
+				Open:      decimal.NewFromInt(100),
+				High:      decimal.NewFromInt(101),
+				Low:       decimal.NewFromInt(100),
+				Close:     decimal.NewFromInt(101),
+				Volume:    decimal.NewFromInt(2),
+				Start:     time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+				BuyVolume: decimal.NewFromInt(2),
+				Ticks:     2,
+				Upticks:   1,
+			},
+			{
+				Open:      decimal.NewFromInt(102),
+				High:      decimal.NewFromInt(102),
+				Low:       decimal.NewFromInt(102),
+				Close:     decimal.NewFromInt(102),
+				Volume:    decimal.NewFromInt(1),
+				Start:     time.Date(2025, 1, 1, 10, 0, 0, int(250*time.Millisecond), time.UTC),
+				BuyVolume: decimal.NewFromInt(1),
+				Ticks:     1,
+			},
+		},
+	}
+
+	p, err := bartender.New(bartender.WithInterval(tc.input))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tc.Run(t, p)
+}
+
+func TestTimeBarConfig_Alignment(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+
+	tt := []TestCase[time.Duration]{
+		{
+			name:  "Anchored to Session Open",
+			input: 4 * time.Hour,
+			trades: []bartender.Trade{
+				{Price: decimal.NewFromInt(100), Side: bartender.SideBuy, Size: decimal.NewFromInt(1), Time: time.Date(2025, 1, 2, 14, 45, 0, 0, time.UTC)},
+				{Price: decimal.NewFromInt(101), Side: bartender.SideBuy, Size: decimal.NewFromInt(1), Time: time.Date(2025, 1, 2, 18, 29, 0, 0, time.UTC)},
+				{Price: decimal.NewFromInt(102), Side: bartender.SideBuy, Size: decimal.NewFromInt(1), Time: time.Date(2025, 1, 2, 18, 31, 0, 0, time.UTC)},
+			},
+			want: []bartender.Bar{
+				{
+					Open:      decimal.NewFromInt(100),
+					High:      decimal.NewFromInt(101),
+					Low:       decimal.NewFromInt(100),
+					Close:     decimal.NewFromInt(101),
+					Volume:    decimal.NewFromInt(2),
+					Start:     time.Date(2025, 1, 2, 9, 30, 0, 0, newYork),
+					BuyVolume: decimal.NewFromInt(2),
+					Ticks:     2,
+					Upticks:   1,
+				},
+				{
+					Open:      decimal.NewFromInt(102),
+					High:      decimal.NewFromInt(102),
+					Low:       decimal.NewFromInt(102),
+					Close:     decimal.NewFromInt(102),
+					Volume:    decimal.NewFromInt(1),
+					Start:     time.Date(2025, 1, 2, 13, 30, 0, 0, newYork),
+					BuyVolume: decimal.NewFromInt(1),
+					Ticks:     1,
+				},
+			},
+		},
+		{
+			name:  "Anchored Across Daylight Saving Time",
+			input: 4 * time.Hour,
+			trades: []bartender.Trade{
+				{Price: decimal.NewFromInt(100), Side: bartender.SideBuy, Size: decimal.NewFromInt(1), Time: time.Date(2025, 3, 10, 13, 35, 0, 0, time.UTC)},
+			},
+			want: []bartender.Bar{
+				{
+					Open:      decimal.NewFromInt(100),
+					High:      decimal.NewFromInt(100),
+					Low:       decimal.NewFromInt(100),
+					Close:     decimal.NewFromInt(100),
+					Volume:    decimal.NewFromInt(1),
+					Start:     time.Date(2025, 3, 10, 9, 30, 0, 0, newYork),
+					BuyVolume: decimal.NewFromInt(1),
+					Ticks:     1,
+				},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		p, err := bartender.New(bartender.WithInterval(tc.input), bartender.WithAlignment(newYork, 9*time.Hour+30*time.Minute))
+		if err != nil {
+			t.Errorf("New() error = %v", err)
+			return
+		}
+
+		tc.Run(t, p)
+	}
+}
+
+func TestTimeBarConfig_AlignmentBoundaries(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+
+	type interval struct {
+		start, end time.Time
+	}
+
+	tt := []struct {
+		name     string
+		interval time.Duration
+		location *time.Location
+		offset   time.Duration
+		trades   []time.Time
+		want     []interval
+	}{
+		{
+			// 7h does not divide the day, so the last interval of each day is cut at the anchor
+			name:     "Interval Not Dividing the Day",
+			interval: 7 * time.Hour,
+			location: time.UTC,
+			trades:   []time.Time{time.Date(2025, 1, 1, 22, 0, 0, 0, time.UTC), time.Date(2025, 1, 2, 20, 0, 0, 0, time.UTC)},
+			want: []interval{
+				{time.Date(2025, 1, 1, 21, 0, 0, 0, time.UTC), time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)},
+				{time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 2, 7, 0, 0, 0, time.UTC)},
+				{time.Date(2025, 1, 2, 7, 0, 0, 0, time.UTC), time.Date(2025, 1, 2, 14, 0, 0, 0, time.UTC)},
+				{time.Date(2025, 1, 2, 14, 0, 0, 0, time.UTC), time.Date(2025, 1, 2, 21, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			// the clocks go forward at 02:00 on March 9, so the interval before the anchor is cut to 3h
+			name:     "Daylight Saving Time",
+			interval: 4 * time.Hour,
+			location: newYork,
+			offset:   9*time.Hour + 30*time.Minute,
+			trades:   []time.Time{time.Date(2025, 3, 8, 15, 0, 0, 0, newYork), time.Date(2025, 3, 9, 10, 0, 0, 0, newYork)},
+			want: []interval{
+				{time.Date(2025, 3, 8, 13, 30, 0, 0, newYork), time.Date(2025, 3, 8, 17, 30, 0, 0, newYork)},
+				{time.Date(2025, 3, 8, 17, 30, 0, 0, newYork), time.Date(2025, 3, 8, 21, 30, 0, 0, newYork)},
+				{time.Date(2025, 3, 8, 21, 30, 0, 0, newYork), time.Date(2025, 3, 9, 1, 30, 0, 0, newYork)},
+				{time.Date(2025, 3, 9, 1, 30, 0, 0, newYork), time.Date(2025, 3, 9, 6, 30, 0, 0, newYork)},
+				{time.Date(2025, 3, 9, 6, 30, 0, 0, newYork), time.Date(2025, 3, 9, 9, 30, 0, 0, newYork)},
+				{time.Date(2025, 3, 9, 9, 30, 0, 0, newYork), time.Date(2025, 3, 9, 13, 30, 0, 0, newYork)},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := bartender.New(
+				bartender.WithInterval(tc.interval),
+				bartender.WithAlignment(tc.location, tc.offset),
+				bartender.WithoutSessionBoundary[bartender.TimeBarConfig](),
+			)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			trades := make([]bartender.Trade, len(tc.trades))
+			for i, at := range tc.trades {
+				trades[i] = bartender.Trade{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Time: at}
+			}
+
+			bars, err := bartender.Generate(trades, p)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			got := make([]interval, len(bars))
+			for i, bar := range bars {
+				got[i] = interval{bar.Start, bar.End}
+			}
+
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(interval{}), cmpopts.EquateApproxTime(0)); diff != "" {
+				t.Errorf("Generate() intervals (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTimeBarConfig_SessionRollover(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+
+	type interval struct {
+		start, end time.Time
+		ticks      int
+		reason     bartender.CloseReason
+	}
+
+	tt := []struct {
+		name    string
+		options []bartender.Option[bartender.TimeBarConfig]
+		trades  []time.Time
+		want    []interval
+	}{
+		{
+			// the session rolls over at 22:00, partway through the 20:00 interval
+			name:    "Rollover Inside an Interval",
+			options: []bartender.Option[bartender.TimeBarConfig]{bartender.WithSessionBoundary[bartender.TimeBarConfig](22*time.Hour, time.UTC)},
+			trades: []time.Time{
+				time.Date(2025, 1, 1, 21, 0, 0, 0, time.UTC),
+				time.Date(2025, 1, 1, 22, 30, 0, 0, time.UTC),
+				time.Date(2025, 1, 2, 0, 30, 0, 0, time.UTC),
+				time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC),
+			},
+			want: []interval{
+				{time.Date(2025, 1, 1, 20, 0, 0, 0, time.UTC), time.Date(2025, 1, 1, 22, 0, 0, 0, time.UTC), 1, bartender.CloseReasonSession},
+				{time.Date(2025, 1, 1, 22, 0, 0, 0, time.UTC), time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), 1, bartender.CloseReasonInterval},
+				{time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 2, 4, 0, 0, 0, time.UTC), 2, bartender.CloseReasonEndOfStream},
+			},
+		},
+		{
+			// the default session rolls over at midnight UTC, 19:00 in New York, between the anchored intervals
+			name:    "Anchored Intervals and the Default Rollover",
+			options: []bartender.Option[bartender.TimeBarConfig]{bartender.WithAlignment(newYork, 9*time.Hour+30*time.Minute)},
+			trades: []time.Time{
+				time.Date(2025, 1, 2, 18, 0, 0, 0, newYork),
+				time.Date(2025, 1, 2, 22, 0, 0, 0, newYork),
+			},
+			want: []interval{
+				{time.Date(2025, 1, 2, 17, 30, 0, 0, newYork), time.Date(2025, 1, 2, 19, 0, 0, 0, newYork), 1, bartender.CloseReasonSession},
+				{time.Date(2025, 1, 2, 19, 0, 0, 0, newYork), time.Date(2025, 1, 2, 21, 30, 0, 0, newYork), 0, bartender.CloseReasonInterval},
+				{time.Date(2025, 1, 2, 21, 30, 0, 0, newYork), time.Date(2025, 1, 3, 1, 30, 0, 0, newYork), 1, bartender.CloseReasonEndOfStream},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := bartender.New(append(tc.options, bartender.WithInterval(4*time.Hour))...)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			trades := make([]bartender.Trade, len(tc.trades))
+			for i, at := range tc.trades {
+				trades[i] = bartender.Trade{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Time: at}
+			}
+
+			bars, err := bartender.Generate(trades, p)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			got := make([]interval, len(bars))
+			for i, bar := range bars {
+				got[i] = interval{bar.Start, bar.End, bar.Ticks, bar.CloseReason}
+			}
+
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(interval{}), cmpopts.EquateApproxTime(0)); diff != "" {
+				t.Errorf("Generate() intervals (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTimeBarConfig_InvalidInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Minute} {
+		if _, err := bartender.New(bartender.WithInterval(interval)); err == nil {
+			t.Errorf("New(WithInterval(%s)) error = nil, want error", interval)
+		}
+	}
+}
+
+func TestTimeBarConfig_Calendar(t *testing.T) {
+	tc := TestCase[time.Duration]{
+		name:  "Skips Gaps Outside Regular Session",
+		input: time.Hour,
+		trades: []bartender.Trade{
+			{Price: decimal.NewFromInt(100), Side: bartender.SideBuy, Size: decimal.NewFromInt(1), Time: time.Date(2025, 1, 3, 20, 30, 0, 0, time.UTC)},
+			{Price: decimal.NewFromInt(101), Side: bartender.SideBuy, Size: decimal.NewFromInt(1), Time: time.Date(2025, 1, 6, 14, 30, 0, 0, time.UTC)},
+		},
+		want: []bartender.Bar{
+			{
+				Open:      decimal.NewFromInt(100),
+				High:      decimal.NewFromInt(100),
+				Low:       decimal.NewFromInt(100),
+				Close:     decimal.NewFromInt(100),
+				Volume:    decimal.NewFromInt(1),
+				Start:     time.Date(2025, 1, 3, 20, 0, 0, 0, time.UTC),
+				BuyVolume: decimal.NewFromInt(1),
+				Ticks:     1,
+			},
+			{
+				Open:      decimal.NewFromInt(101),
+				High:      decimal.NewFromInt(101),
+				Low:       decimal.NewFromInt(101),
+				Close:     decimal.NewFromInt(101),
+				Volume:    decimal.NewFromInt(1),
+				Start:     time.Date(2025, 1, 6, 14, 0, 0, 0, time.UTC),
+				BuyVolume: decimal.NewFromInt(1),
+				Ticks:     1,
+			},
+		},
+	}
+
+	p, err := bartender.New(bartender.WithInterval(tc.input), bartender.WithCalendar(bartender.USEquities(), bartender.SessionRegular))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tc.Run(t, p)
+}
+
+func TestTimeBarConfig_Clock(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	clock := bartender.NewFakeClock(start)
+
+	p, err := bartender.New(bartender.WithInterval(time.Minute), bartender.WithClock(clock, 5*time.Second))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	trades := make(chan bartender.Trade)
+	bars := p.Process(trades)
+
+	trades <- bartender.Trade{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: start.Add(10 * time.Second)}
+
+	// a late trade within the delay is added to the bar
+	clock.Advance(time.Minute)
+	trades <- bartender.Trade{Price: decimal.NewFromInt(101), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: start.Add(59 * time.Second)}
+
+	clock.Advance(5 * time.Second)
+
+	want := []bartender.Bar{
+		{
+			Open:      decimal.NewFromInt(100),
+			High:      decimal.NewFromInt(101),
+			Low:       decimal.NewFromInt(100),
+			Close:     decimal.NewFromInt(101),
+			Volume:    decimal.NewFromInt(2),
+			Start:     start,
+			BuyVolume: decimal.NewFromInt(2),
+			Ticks:     2,
+			Upticks:   1,
+		},
+		{
+			Open:  decimal.NewFromInt(101),
+			High:  decimal.NewFromInt(101),
+			Low:   decimal.NewFromInt(101),
+			Close: decimal.NewFromInt(101),
+			Start: start.Add(time.Minute),
+		},
+		{
+			Open:      decimal.NewFromInt(102),
+			High:      decimal.NewFromInt(102),
+			Low:       decimal.NewFromInt(102),
+			Close:     decimal.NewFromInt(102),
+			Volume:    decimal.NewFromInt(1),
+			Start:     start.Add(2 * time.Minute),
+			BuyVolume: decimal.NewFromInt(1),
+			Ticks:     1,
+		},
+	}
+
+	got := []bartender.Bar{*<-bars}
+
+	// an interval without trades is closed as an empty bar
+	clock.Advance(time.Minute)
+	got = append(got, *<-bars)
+
+	trades <- bartender.Trade{Price: decimal.NewFromInt(102), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: start.Add(2*time.Minute + 30*time.Second)}
+	close(trades)
+
+	for bar := range bars {
+		got = append(got, *bar)
+	}
+
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreUnexported(bartender.Bar{}), ignoreBarStats); diff != "" {
+		t.Errorf("Process() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestTimeBarConfig_Revisions(t *testing.T) {
+	tc := TestCase[time.Duration]{
+		name:  "Out of Order Trade in Bar in Progress",
+		input: time.Minute,
+		trades: []bartender.Trade{
+			{Price: decimal.NewFromInt(101), Side: bartender.SideBuy, Size: decimal.NewFromInt(1), Time: time.Date(2025, 1, 1, 10, 0, 30, 0, time.UTC)},
+			{Price: decimal.NewFromInt(100), Side: bartender.SideSell, Size: decimal.NewFromInt(1), Time: time.Date(2025, 1, 1, 10, 0, 10, 0, time.UTC)},
+		},
+		want: []bartender.Bar{
+			{
+				Open:       decimal.NewFromInt(100),
+				High:       decimal.NewFromInt(101),
+				Low:        decimal.NewFromInt(100),
+				Close:      decimal.NewFromInt(101),
+				Volume:     decimal.NewFromInt(2),
+				Start:      time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+				BuyVolume:  decimal.NewFromInt(1),
+				SellVolume: decimal.NewFromInt(1),
+				Ticks:      2,
+				Upticks:    1,
+			},
+		},
+	}
+
+	p, err := bartender.New(bartender.WithInterval(tc.input), bartender.WithRevisions(time.Hour))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tc.Run(t, p)
+}
+
+func TestTimeBarConfig_Amendments(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	tc := TestCase[time.Duration]{
+		name:  "Cancels and Corrections",
+		input: time.Minute,
+		trades: []bartender.Trade{
+			{ID: "1", Price: decimal.NewFromInt(100), Side: bartender.SideBuy, Size: decimal.NewFromInt(1), Time: start.Add(10 * time.Second)},
+			{ID: "2", Price: decimal.NewFromInt(105), Side: bartender.SideBuy, Size: decimal.NewFromInt(1), Time: start.Add(20 * time.Second)},
+			{ID: "3", Price: decimal.NewFromInt(102), Side: bartender.SideBuy, Size: decimal.NewFromInt(1), Time: start.Add(70 * time.Second)},
+			// cancel a trade of the emitted first bar
+			{ID: "2", Action: bartender.ActionCancel, Time: start.Add(75 * time.Second)},
+			// correct the size of a trade in the bar in progress
+			{ID: "3", Action: bartender.ActionCorrect, Price: decimal.NewFromInt(102), Side: bartender.SideBuy, Size: decimal.NewFromInt(5), Time: start.Add(70 * time.Second)},
+			// move a trade from the first bar to the bar in progress
+			{ID: "1", Action: bartender.ActionCorrect, Price: decimal.NewFromInt(101), Side: bartender.SideSell, Size: decimal.NewFromInt(1), Time: start.Add(65 * time.Second)},
+			// unknown trades are ignored
+			{ID: "9", Action: bartender.ActionCancel, Time: start.Add(80 * time.Second)},
+		},
+		want: []bartender.Bar{
+			{
+				Open:      decimal.NewFromInt(100),
+				High:      decimal.NewFromInt(105),
+				Low:       decimal.NewFromInt(100),
+				Close:     decimal.NewFromInt(105),
+				Volume:    decimal.NewFromInt(2),
+				Start:     start,
+				BuyVolume: decimal.NewFromInt(2),
+				Ticks:     2,
+				Upticks:   1,
+			},
+			{
+				Open:      decimal.NewFromInt(100),
+				High:      decimal.NewFromInt(100),
+				Low:       decimal.NewFromInt(100),
+				Close:     decimal.NewFromInt(100),
+				Volume:    decimal.NewFromInt(1),
+				Start:     start,
+				BuyVolume: decimal.NewFromInt(1),
+				Ticks:     1,
+				Revision:  1,
+			},
+			{
+				Start:    start,
+				Revision: 2,
+			},
+			{
+				Open:       decimal.NewFromInt(101),
+				High:       decimal.NewFromInt(102),
+				Low:        decimal.NewFromInt(101),
+				Close:      decimal.NewFromInt(102),
+				Volume:     decimal.NewFromInt(6),
+				Start:      start.Add(time.Minute),
+				BuyVolume:  decimal.NewFromInt(5),
+				SellVolume: decimal.NewFromInt(1),
+				Ticks:      2,
+				Upticks:    1,
+			},
+		},
+	}
+
+	p, err := bartender.New(bartender.WithInterval(tc.input), bartender.WithRevisions(time.Hour))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tc.Run(t, p)
+}
+
+func TestTimeBarConfig_CancelledBar(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	tc := TestCase[time.Duration]{
+		name:  "Carries the Previous Close",
+		input: time.Minute,
+		trades: []bartender.Trade{
+			{ID: "1", Symbol: "AAPL", Price: decimal.NewFromInt(100), Side: bartender.SideBuy, Size: decimal.NewFromInt(1), Time: start.Add(10 * time.Second)},
+			{ID: "2", Symbol: "AAPL", Price: decimal.NewFromInt(105), Side: bartender.SideBuy, Size: decimal.NewFromInt(1), Time: start.Add(70 * time.Second)},
+			// the first bar passes the revision horizon
+			{ID: "3", Symbol: "AAPL", Price: decimal.NewFromInt(107), Side: bartender.SideBuy, Size: decimal.NewFromInt(1), Time: start.Add(150 * time.Second)},
+			// cancel every trade of the second bar
+			{ID: "2", Action: bartender.ActionCancel, Time: start.Add(155 * time.Second)},
+		},
+		want: []bartender.Bar{
+			{
+				Symbol:    "AAPL",
+				Open:      decimal.NewFromInt(100),
+				High:      decimal.NewFromInt(100),
+				Low:       decimal.NewFromInt(100),
+				Close:     decimal.NewFromInt(100),
+				Volume:    decimal.NewFromInt(1),
+				Start:     start,
+				BuyVolume: decimal.NewFromInt(1),
+				Ticks:     1,
+			},
+			{
+				Symbol:    "AAPL",
+				Open:      decimal.NewFromInt(105),
+				High:      decimal.NewFromInt(105),
+				Low:       decimal.NewFromInt(105),
+				Close:     decimal.NewFromInt(105),
+				Volume:    decimal.NewFromInt(1),
+				Start:     start.Add(time.Minute),
+				BuyVolume: decimal.NewFromInt(1),
+				Ticks:     1,
+			},
+			{
+				Symbol:   "AAPL",
+				Open:     decimal.NewFromInt(100),
+				High:     decimal.NewFromInt(100),
+				Low:      decimal.NewFromInt(100),
+				Close:    decimal.NewFromInt(100),
+				Start:    start.Add(time.Minute),
+				Revision: 1,
+			},
+			{
+				Symbol:    "AAPL",
+				Open:      decimal.NewFromInt(107),
+				High:      decimal.NewFromInt(107),
+				Low:       decimal.NewFromInt(107),
+				Close:     decimal.NewFromInt(107),
+				Volume:    decimal.NewFromInt(1),
+				Start:     start.Add(2 * time.Minute),
+				BuyVolume: decimal.NewFromInt(1),
+				Ticks:     1,
+			},
+		},
+	}
+
+	p, err := bartender.New(bartender.WithInterval(tc.input), bartender.WithRevisions(time.Minute))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tc.Run(t, p)
+}
@@ -20,10 +20,30 @@ const (
 	SideSell Side = "sell"
 )
 
+// Action marks a trade that cancels or corrects an earlier trade.
+type Action string
+
+const (
+	// ActionCancel cancels the earlier trade with the same ID.
+	ActionCancel Action = "cancel"
+	// ActionCorrect replaces the earlier trade with the same ID by this trade.
+	ActionCorrect Action = "correct"
+)
+
 type Trade struct {
 	Symbol string          `json:"symbol"`
 	Price  decimal.Decimal `json:"price"`
 	Size   decimal.Decimal `json:"size"`
 	Side   Side            `json:"side"`
 	Time   time.Time       `json:"time"`
+
+	// ID identifies the trade for later cancels and corrections.
+	ID string `json:"id"`
+	// Action is empty for a new trade. Cancels and corrections refer to an earlier trade by its ID and are only
+	// applied by time bars configured WithRevisions; other processors ignore them.
+	Action Action `json:"action"`
+
+	// BuyRatio is the share of Size attributed to buyers when Side is unknown. It is set by probabilistic
+	// classifiers such as BulkVolume. Trades without a Side or BuyRatio are counted as sell volume.
+	BuyRatio decimal.Decimal `json:"buy_ratio"`
 }
@@ -8,6 +8,8 @@ Licensed under the MIT license. See LICENSE file in the project root for details
 package bartender
 
 import (
+	"context"
+
 	decimal "github.com/alpacahq/alpacadecimal"
 )
 
@@ -18,10 +20,16 @@ func WithVolumeThreshold(threshold float64) Option[VolumeBarConfig] {
 }
 
 type VolumeBarConfig struct {
+	processorConfig
+
 	volumeThreshold decimal.Decimal
 }
 
 func (c VolumeBarConfig) Process(trades <-chan Trade) chan *Bar {
+	return c.ProcessContext(context.Background(), trades)
+}
+
+func (c VolumeBarConfig) ProcessContext(ctx context.Context, trades <-chan Trade) chan *Bar {
 	output := make(chan *Bar)
 
 	go func() {
@@ -29,35 +37,53 @@ func (c VolumeBarConfig) Process(trades <-chan Trade) chan *Bar {
 
 		var current *Bar
 
-		for trade := range trades {
+		// resume from the checkpoint, if any
+		var state accumulatorState
+		if c.restore(&state) {
+			current = state.Current.bar()
+		}
+
+		partial := c.partials()
+
+		for trade := range c.newTrades(ctx, trades) {
 			// initialize the current bar if it doesn't exist
 			if current == nil {
 				current = &Bar{}
 			}
 
-			// check if the trade is on a new day
-			if !current.Start.IsZero() && current.Start.Weekday() != trade.Time.Weekday() {
+			// check if the trade is in a new session
+			if c.session.crossed(current.Start, trade.Time) {
 				finalizedBar := current
 
-				output <- finalizedBar
+				if !c.closeBar(ctx, output, finalizedBar, CloseReasonSession) {
+					return
+				}
 
 				// reset the current bar
 				current = &Bar{}
 			}
 
-			current.applyTrade(trade)
+			c.apply(current, trade)
 
 			if current.Volume.GreaterThanOrEqual(c.volumeThreshold) {
 				finalizedBar := current
-				output <- finalizedBar
+				if !c.closeBar(ctx, output, finalizedBar, CloseReasonThreshold) {
+					return
+				}
 
 				// reset the current bar
 				current = nil
 			}
+
+			if !partial.publish(ctx, output, trade, current) {
+				return
+			}
+
+			c.save(trade, accumulatorState{Current: snapshotBar(current)})
 		}
 
 		if current != nil {
-			output <- current
+			c.closeBar(ctx, output, current, CloseReasonEndOfStream)
 		}
 	}()
 
@@ -71,10 +97,16 @@ func WithVolumeImbalanceThreshold(threshold float64) Option[VolumeImbalanceBarCo
 }
 
 type VolumeImbalanceBarConfig struct {
+	processorConfig
+
 	imbalanceThreshold decimal.Decimal
 }
 
 func (c VolumeImbalanceBarConfig) Process(trades <-chan Trade) chan *Bar {
+	return c.ProcessContext(context.Background(), trades)
+}
+
+func (c VolumeImbalanceBarConfig) ProcessContext(ctx context.Context, trades <-chan Trade) chan *Bar {
 	output := make(chan *Bar)
 
 	go func() {
@@ -84,7 +116,15 @@ func (c VolumeImbalanceBarConfig) Process(trades <-chan Trade) chan *Bar {
 		var netImbalance decimal.Decimal
 		var prevPrice decimal.Decimal
 
-		for trade := range trades {
+		// resume from the checkpoint, if any
+		var state accumulatorState
+		if c.restore(&state) {
+			current, netImbalance, prevPrice = state.Current.bar(), state.Value, state.PrevPrice
+		}
+
+		partial := c.partials()
+
+		for trade := range c.newTrades(ctx, trades) {
 			// initialize the last price if not already set
 			if prevPrice.IsZero() {
 				prevPrice = trade.Price
@@ -95,18 +135,20 @@ func (c VolumeImbalanceBarConfig) Process(trades <-chan Trade) chan *Bar {
 				current = &Bar{}
 			}
 
-			// check if the trade is on a new day
-			if !current.Start.IsZero() && current.Start.Weekday() != trade.Time.Weekday() {
+			// check if the trade is in a new session
+			if c.session.crossed(current.Start, trade.Time) {
 				finalizedBar := current
 
-				output <- finalizedBar
+				if !c.closeBar(ctx, output, finalizedBar, CloseReasonSession) {
+					return
+				}
 
 				// reset the current bar
 				current = &Bar{}
 				netImbalance = decimal.Zero
 			}
 
-			current.applyTrade(trade)
+			c.apply(current, trade)
 
 			// update net imbalance
 			if trade.Price.GreaterThan(prevPrice) {
@@ -119,17 +161,25 @@ func (c VolumeImbalanceBarConfig) Process(trades <-chan Trade) chan *Bar {
 
 			if netImbalance.Abs().GreaterThanOrEqual(c.imbalanceThreshold) {
 				finalizedBar := current
-				output <- finalizedBar
+				if !c.closeBar(ctx, output, finalizedBar, CloseReasonThreshold) {
+					return
+				}
 
 				// reset the current bar
 				current = nil
 				// reset the net imbalance
 				netImbalance = decimal.Zero
 			}
+
+			if !partial.publish(ctx, output, trade, current) {
+				return
+			}
+
+			c.save(trade, accumulatorState{Current: snapshotBar(current), Value: netImbalance, PrevPrice: prevPrice})
 		}
 
 		if current != nil {
-			output <- current
+			c.closeBar(ctx, output, current, CloseReasonEndOfStream)
 		}
 	}()
 
@@ -143,10 +193,16 @@ func WithVolumeRunThreshold(threshold float64) Option[VolumeRunBarConfig] {
 }
 
 type VolumeRunBarConfig struct {
+	processorConfig
+
 	runVolumeThreshold decimal.Decimal
 }
 
 func (c VolumeRunBarConfig) Process(trades <-chan Trade) chan *Bar {
+	return c.ProcessContext(context.Background(), trades)
+}
+
+func (c VolumeRunBarConfig) ProcessContext(ctx context.Context, trades <-chan Trade) chan *Bar {
 	output := make(chan *Bar)
 
 	go func() {
@@ -156,7 +212,15 @@ func (c VolumeRunBarConfig) Process(trades <-chan Trade) chan *Bar {
 		var upwardVolumeRun, downwardVolumeRun decimal.Decimal
 		var prevPrice decimal.Decimal
 
-		for trade := range trades {
+		// resume from the checkpoint, if any
+		var state runState
+		if c.restore(&state) {
+			current, upwardVolumeRun, downwardVolumeRun, prevPrice = state.Current.bar(), state.Up, state.Down, state.PrevPrice
+		}
+
+		partial := c.partials()
+
+		for trade := range c.newTrades(ctx, trades) {
 			// initialize the last price if not already set
 			if prevPrice.IsZero() {
 				prevPrice = trade.Price
@@ -167,11 +231,13 @@ func (c VolumeRunBarConfig) Process(trades <-chan Trade) chan *Bar {
 				current = &Bar{}
 			}
 
-			// check if the trade is on a new day
-			if !current.Start.IsZero() && current.Start.Weekday() != trade.Time.Weekday() {
+			// check if the trade is in a new session
+			if c.session.crossed(current.Start, trade.Time) {
 				finalizedBar := current
 
-				output <- finalizedBar
+				if !c.closeBar(ctx, output, finalizedBar, CloseReasonSession) {
+					return
+				}
 
 				// reset the current bar
 				current = &Bar{}
@@ -180,7 +246,7 @@ func (c VolumeRunBarConfig) Process(trades <-chan Trade) chan *Bar {
 				downwardVolumeRun = decimal.Zero
 			}
 
-			current.applyTrade(trade)
+			c.apply(current, trade)
 
 			// determine the direction of the tick and update volume runs
 			if trade.Price.GreaterThan(prevPrice) {
@@ -196,7 +262,9 @@ func (c VolumeRunBarConfig) Process(trades <-chan Trade) chan *Bar {
 			// check if a new bar should be created based on the volume run threshold
 			if upwardVolumeRun.GreaterThan(c.runVolumeThreshold) || downwardVolumeRun.GreaterThan(c.runVolumeThreshold) {
 				finalizedBar := current
-				output <- finalizedBar
+				if !c.closeBar(ctx, output, finalizedBar, CloseReasonThreshold) {
+					return
+				}
 
 				// reset the current bar
 				current = nil
@@ -204,17 +272,92 @@ func (c VolumeRunBarConfig) Process(trades <-chan Trade) chan *Bar {
 				upwardVolumeRun = decimal.Zero
 				downwardVolumeRun = decimal.Zero
 			}
+
+			if !partial.publish(ctx, output, trade, current) {
+				return
+			}
+
+			c.save(trade, runState{Current: snapshotBar(current), Up: upwardVolumeRun, Down: downwardVolumeRun, PrevPrice: prevPrice})
 		}
 
 		if current != nil {
-			output <- current
+			c.closeBar(ctx, output, current, CloseReasonEndOfStream)
 		}
 	}()
 
 	return output
 }
 
+// WithAdaptiveVolumeImbalance configures a volume imbalance bar whose threshold adapts to previous bars. warmup is the
+// expected number of ticks per bar until the first bar closes and span the span of the moving averages.
+func WithAdaptiveVolumeImbalance(warmup, span int64) Option[AdaptiveVolumeImbalanceBarConfig] {
+	return func(c *AdaptiveVolumeImbalanceBarConfig) {
+		c.warmup = warmup
+		c.span = span
+	}
+}
+
+// WithAdaptiveVolumeImbalanceLimits clamps the expected number of ticks per bar.
+func WithAdaptiveVolumeImbalanceLimits(minTicks, maxTicks int64) Option[AdaptiveVolumeImbalanceBarConfig] {
+	return func(c *AdaptiveVolumeImbalanceBarConfig) {
+		c.minTicks = minTicks
+		c.maxTicks = maxTicks
+	}
+}
+
+// AdaptiveVolumeImbalanceBarConfig closes a bar once the signed volume imbalance exceeds the expected imbalance,
+// estimated from previous bars.
+type AdaptiveVolumeImbalanceBarConfig struct {
+	adaptiveConfig
+}
+
+func (c AdaptiveVolumeImbalanceBarConfig) Process(trades <-chan Trade) chan *Bar {
+	return c.ProcessContext(context.Background(), trades)
+}
+
+func (c AdaptiveVolumeImbalanceBarConfig) ProcessContext(ctx context.Context, trades <-chan Trade) chan *Bar {
+	return c.processImbalance(ctx, trades, volumeMeasure)
+}
+
+// WithAdaptiveVolumeRun configures a volume run bar whose threshold adapts to previous bars. warmup is the expected
+// number of ticks per bar until the first bar closes and span the span of the moving averages.
+func WithAdaptiveVolumeRun(warmup, span int64) Option[AdaptiveVolumeRunBarConfig] {
+	return func(c *AdaptiveVolumeRunBarConfig) {
+		c.warmup = warmup
+		c.span = span
+	}
+}
+
+// WithAdaptiveVolumeRunLimits clamps the expected number of ticks per bar.
+func WithAdaptiveVolumeRunLimits(minTicks, maxTicks int64) Option[AdaptiveVolumeRunBarConfig] {
+	return func(c *AdaptiveVolumeRunBarConfig) {
+		c.minTicks = minTicks
+		c.maxTicks = maxTicks
+	}
+}
+
+// AdaptiveVolumeRunBarConfig closes a bar once the larger of the buy and sell volume in the bar exceeds the expected
+// run, estimated from previous bars.
+type AdaptiveVolumeRunBarConfig struct {
+	adaptiveConfig
+}
+
+func (c AdaptiveVolumeRunBarConfig) Process(trades <-chan Trade) chan *Bar {
+	return c.ProcessContext(context.Background(), trades)
+}
+
+func (c AdaptiveVolumeRunBarConfig) ProcessContext(ctx context.Context, trades <-chan Trade) chan *Bar {
+	return c.processRuns(ctx, trades, volumeMeasure)
+}
+
 // Interface guards
 var _ Processor = (*VolumeBarConfig)(nil)
 var _ Processor = (*VolumeImbalanceBarConfig)(nil)
 var _ Processor = (*VolumeRunBarConfig)(nil)
+var _ Processor = (*AdaptiveVolumeImbalanceBarConfig)(nil)
+var _ Processor = (*AdaptiveVolumeRunBarConfig)(nil)
+var _ ContextProcessor = (*VolumeBarConfig)(nil)
+var _ ContextProcessor = (*VolumeImbalanceBarConfig)(nil)
+var _ ContextProcessor = (*VolumeRunBarConfig)(nil)
+var _ ContextProcessor = (*AdaptiveVolumeImbalanceBarConfig)(nil)
+var _ ContextProcessor = (*AdaptiveVolumeRunBarConfig)(nil)
@@ -230,3 +230,145 @@ func TestVolumeRunBarConfig_Process(t *testing.T) {
 		tc.Run(t, p)
 	}
 }
+
+func TestAdaptiveVolumeImbalanceBarConfig_Process(t *testing.T) {
+	type adaptiveInput struct {
+		warmup, span, minTicks, maxTicks int64
+	}
+
+	tt := []TestCase[adaptiveInput]{
+		{
+			name:  "Expected Imbalance Trigger",
+			input: adaptiveInput{warmup: 4, span: 3},
+			trades: []bartender.Trade{
+				{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(2), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)},
+				{Price: decimal.NewFromInt(101), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 10, 0, time.UTC)},
+				{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(1), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 20, 0, time.UTC)},
+				{Price: decimal.NewFromInt(101), Size: decimal.NewFromInt(2), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 30, 0, time.UTC)},
+				{Price: decimal.NewFromInt(102), Size: decimal.NewFromInt(2), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 40, 0, time.UTC)},
+				{Price: decimal.NewFromInt(103), Size: decimal.NewFromInt(2), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 50, 0, time.UTC)},
+				{Price: decimal.NewFromInt(102), Size: decimal.NewFromInt(1), Side: bartender.SideSell, Time: time.Date(2025, 1, 1, 10, 0, 55, 0, time.UTC)},
+			},
+			want: []bartender.Bar{
+				{
+					Open:      decimal.NewFromInt(100),
+					High:      decimal.NewFromInt(103),
+					Low:       decimal.NewFromInt(100),
+					Close:     decimal.NewFromInt(103),
+					Volume:    decimal.NewFromInt(10),
+					Start:     time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+					BuyVolume: decimal.NewFromInt(10),
+					Ticks:     6,
+					Upticks:   4,
+					Threshold: decimal.NewFromFloat(7.125),
+				},
+				{
+					Open:       decimal.NewFromInt(102),
+					High:       decimal.NewFromInt(102),
+					Low:        decimal.NewFromInt(102),
+					Close:      decimal.NewFromInt(102),
+					Volume:     decimal.NewFromInt(1),
+					Start:      time.Date(2025, 1, 1, 10, 0, 55, 0, time.UTC),
+					SellVolume: decimal.NewFromInt(1),
+					Ticks:      1,
+					Threshold:  decimal.NewFromFloat(1.953125),
+				},
+			},
+		},
+		{
+			name:   "No Trades",
+			input:  adaptiveInput{warmup: 2},
+			trades: []bartender.Trade{},
+			want:   []bartender.Bar{},
+		},
+	}
+
+	for _, tc := range tt {
+		p, err := bartender.New(
+			bartender.WithAdaptiveVolumeImbalance(tc.input.warmup, tc.input.span),
+			bartender.WithAdaptiveVolumeImbalanceLimits(tc.input.minTicks, tc.input.maxTicks),
+		)
+		if err != nil {
+			t.Errorf("New() error = %v", err)
+			return
+		}
+
+		tc.Run(t, p)
+	}
+}
+
+func TestAdaptiveVolumeRunBarConfig_Process(t *testing.T) {
+	type adaptiveInput struct {
+		warmup, span, minTicks, maxTicks int64
+	}
+
+	tt := []TestCase[adaptiveInput]{
+		{
+			name:  "Expected Run Trigger",
+			input: adaptiveInput{warmup: 2, span: 3},
+			trades: []bartender.Trade{
+				{Price: decimal.NewFromInt(100), Size: decimal.NewFromInt(2), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)},
+				{Price: decimal.NewFromInt(101), Size: decimal.NewFromInt(2), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 10, 0, time.UTC)},
+				{Price: decimal.NewFromInt(102), Size: decimal.NewFromInt(2), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 20, 0, time.UTC)},
+				{Price: decimal.NewFromInt(103), Size: decimal.NewFromInt(2), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 30, 0, time.UTC)},
+				{Price: decimal.NewFromInt(104), Size: decimal.NewFromInt(2), Side: bartender.SideBuy, Time: time.Date(2025, 1, 1, 10, 0, 40, 0, time.UTC)},
+			},
+			want: []bartender.Bar{
+				{
+					Open:      decimal.NewFromInt(100),
+					High:      decimal.NewFromInt(101),
+					Low:       decimal.NewFromInt(100),
+					Close:     decimal.NewFromInt(101),
+					Volume:    decimal.NewFromInt(4),
+					Start:     time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+					BuyVolume: decimal.NewFromInt(4),
+					Ticks:     2,
+					Upticks:   1,
+					Threshold: decimal.NewFromFloat(4),
+				},
+				{
+					Open:      decimal.NewFromInt(102),
+					High:      decimal.NewFromInt(103),
+					Low:       decimal.NewFromInt(102),
+					Close:     decimal.NewFromInt(103),
+					Volume:    decimal.NewFromInt(4),
+					Start:     time.Date(2025, 1, 1, 10, 0, 20, 0, time.UTC),
+					BuyVolume: decimal.NewFromInt(4),
+					Ticks:     2,
+					Upticks:   1,
+					Threshold: decimal.NewFromFloat(4),
+				},
+				{
+					Open:      decimal.NewFromInt(104),
+					High:      decimal.NewFromInt(104),
+					Low:       decimal.NewFromInt(104),
+					Close:     decimal.NewFromInt(104),
+					Volume:    decimal.NewFromInt(2),
+					Start:     time.Date(2025, 1, 1, 10, 0, 40, 0, time.UTC),
+					BuyVolume: decimal.NewFromInt(2),
+					Ticks:     1,
+					Threshold: decimal.NewFromFloat(4),
+				},
+			},
+		},
+		{
+			name:   "No Trades",
+			input:  adaptiveInput{warmup: 2},
+			trades: []bartender.Trade{},
+			want:   []bartender.Bar{},
+		},
+	}
+
+	for _, tc := range tt {
+		p, err := bartender.New(
+			bartender.WithAdaptiveVolumeRun(tc.input.warmup, tc.input.span),
+			bartender.WithAdaptiveVolumeRunLimits(tc.input.minTicks, tc.input.maxTicks),
+		)
+		if err != nil {
+			t.Errorf("New() error = %v", err)
+			return
+		}
+
+		tc.Run(t, p)
+	}
+}